@@ -42,7 +42,8 @@ resource "spheron_instance" "instance_test" {
   #   port = 8000
   # }
 
-  # persistent_storage = {
+  # volume {
+  #   name        = "data"
   #   class       = "HDD"
   #   mount_point = "/etc/data"
   #   size        = 5
@@ -9,8 +9,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type SpheronApi struct {
@@ -18,19 +23,32 @@ type SpheronApi struct {
 	token         string
 
 	organizationId string
+
+	catalogMutex     sync.Mutex
+	computeMachines  []ComputeMachine
+	clusterTemplates []MarketplaceApp
+	regions          []Region
+	instanceLimits   *InstanceLimits
 }
 
-func NewSpheronApi(token string) (*SpheronApi, error) {
+const DefaultSpheronApiUrl = "https://api-v2.spheron.network"
+
+func NewSpheronApi(token string, apiUrl string) (*SpheronApi, error) {
+	if apiUrl == "" {
+		apiUrl = DefaultSpheronApiUrl
+	}
+
 	api := &SpheronApi{
-		spheronApiUrl: "https://api-v2.spheron.network",
+		spheronApiUrl: strings.TrimSuffix(apiUrl, "/"),
 		token:         token,
 	}
 
 	return api, nil
 }
 
-func (api *SpheronApi) sendApiRequest(method string, path string, payload interface{}, params map[string]interface{}) ([]byte, error) {
+func (api *SpheronApi) sendApiRequest(ctx context.Context, method string, path string, payload interface{}, params map[string]interface{}) ([]byte, error) {
 	client := &http.Client{Timeout: 600 * time.Second}
+	ctx = api.logContext(ctx)
 
 	var jsonPayload []byte
 	if payload != nil {
@@ -41,13 +59,16 @@ func (api *SpheronApi) sendApiRequest(method string, path string, payload interf
 		}
 	}
 
-	request, err := http.NewRequest(method, api.spheronApiUrl+path, bytes.NewBuffer(jsonPayload))
+	request, err := http.NewRequestWithContext(ctx, method, api.spheronApiUrl+path, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, err
 	}
 
+	requestID := uuid.New().String()
+
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Authorization", "Bearer "+api.token)
+	request.Header.Set(requestIDHeader, requestID)
 
 	queryParams := request.URL.Query()
 	for key, value := range params {
@@ -55,40 +76,71 @@ func (api *SpheronApi) sendApiRequest(method string, path string, payload interf
 	}
 	request.URL.RawQuery = queryParams.Encode()
 
+	logFields := map[string]interface{}{
+		"method":     method,
+		"path":       path,
+		"request_id": requestID,
+	}
+
+	tflog.SubsystemTrace(ctx, logSubsystem, "Sending Spheron API request", mergeLogFields(logFields, map[string]interface{}{
+		"body": redactBody(jsonPayload),
+	}))
+
+	start := time.Now()
 	response, err := client.Do(request)
+	logFields["duration_ms"] = time.Since(start).Milliseconds()
 	if err != nil {
+		tflog.SubsystemDebug(ctx, logSubsystem, "Spheron API request failed", mergeLogFields(logFields, map[string]interface{}{
+			"error": err.Error(),
+		}))
 		return nil, err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode >= 200 && response.StatusCode < 300 {
-		body, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return nil, err
-		}
-		return body, nil
+	if id := response.Header.Get(requestIDHeader); id != "" {
+		logFields["request_id"] = id
 	}
+	logFields["status"] = response.StatusCode
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return nil, err
 	}
 
+	tflog.SubsystemDebug(ctx, logSubsystem, "Received Spheron API response", logFields)
+	tflog.SubsystemTrace(ctx, logSubsystem, "Spheron API response body", mergeLogFields(logFields, map[string]interface{}{
+		"body": redactBody(body),
+	}))
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return body, nil
+	}
+
 	var errorResponse struct {
 		Message string `json:"message"`
 	}
 	if err := json.Unmarshal(body, &errorResponse); err != nil {
-		return nil, errors.New("API request failed with status: " + response.Status)
+		return nil, &ApiError{StatusCode: response.StatusCode, Message: "API request failed with status: " + response.Status}
 	}
 
-	return nil, errors.New(errorResponse.Message)
+	return nil, &ApiError{StatusCode: response.StatusCode, Message: errorResponse.Message}
+}
+
+// ApiError is returned for requests the Spheron API responded to with an error status.
+type ApiError struct {
+	StatusCode int
+	Message    string
 }
 
-func (api *SpheronApi) getTokenScope() (TokenScope, error) {
+func (e *ApiError) Error() string {
+	return e.Message
+}
+
+func (api *SpheronApi) GetTokenScope(ctx context.Context) (TokenScope, error) {
 	var tokenScope TokenScope
 	path := "/v1/api-keys/scope"
 
-	response, err := api.sendApiRequest(HttpMethodGet, path, nil, nil)
+	response, err := api.sendApiRequest(ctx, HttpMethodGet, path, nil, nil)
 	if err != nil {
 		return tokenScope, err
 	}
@@ -100,9 +152,9 @@ func (api *SpheronApi) getTokenScope() (TokenScope, error) {
 	return tokenScope, nil
 }
 
-func (api *SpheronApi) GetOrganizationId() (string, error) {
+func (api *SpheronApi) GetOrganizationId(ctx context.Context) (string, error) {
 	if api.organizationId == "" {
-		tokenScope, err := api.getTokenScope()
+		tokenScope, err := api.GetTokenScope(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -117,9 +169,9 @@ func (api *SpheronApi) GetOrganizationId() (string, error) {
 	return api.organizationId, nil
 }
 
-func (api *SpheronApi) getOrganizationById(id string) (Organization, error) {
+func (api *SpheronApi) getOrganizationById(ctx context.Context, id string) (Organization, error) {
 	var organization Organization
-	response, err := api.sendApiRequest(HttpMethodGet, fmt.Sprintf("/v1/organization/%s", id), nil, nil)
+	response, err := api.sendApiRequest(ctx, HttpMethodGet, fmt.Sprintf("/v1/organization/%s", id), nil, nil)
 
 	if err != nil {
 		return organization, err
@@ -132,13 +184,13 @@ func (api *SpheronApi) getOrganizationById(id string) (Organization, error) {
 	return organization, nil
 }
 
-func (api *SpheronApi) GetOrganization() (Organization, error) {
-	organizationId, err := api.GetOrganizationId()
+func (api *SpheronApi) GetOrganization(ctx context.Context) (Organization, error) {
+	organizationId, err := api.GetOrganizationId(ctx)
 	if err != nil {
 		return Organization{}, err
 	}
 
-	organization, err := api.getOrganizationById(organizationId)
+	organization, err := api.getOrganizationById(ctx, organizationId)
 	if err != nil {
 		return Organization{}, err
 	}
@@ -146,9 +198,33 @@ func (api *SpheronApi) GetOrganization() (Organization, error) {
 	return organization, nil
 }
 
-func (api *SpheronApi) CreateClusterInstance(clusterInstance CreateInstanceRequest) (InstanceResponse, error) {
+func (api *SpheronApi) GetActiveClusterInstances(ctx context.Context) ([]ActiveInstance, error) {
+	organizationId, err := api.GetOrganizationId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/v1/organization/%s/cluster-instances", organizationId)
+	params := map[string]interface{}{"state": "Active"}
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodGet, path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Instances []ActiveInstance `json:"instances"`
+	}
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	return response.Instances, nil
+}
+
+func (api *SpheronApi) CreateClusterInstance(ctx context.Context, clusterInstance CreateInstanceRequest) (InstanceResponse, error) {
 	var instanceResponse InstanceResponse
-	response, err := api.sendApiRequest(HttpMethodPost, "/v1/cluster-instance/create", clusterInstance, nil)
+	response, err := api.sendApiRequest(ctx, HttpMethodPost, "/v1/cluster-instance/create", clusterInstance, nil)
 	if err != nil {
 		return instanceResponse, err
 	}
@@ -160,10 +236,10 @@ func (api *SpheronApi) CreateClusterInstance(clusterInstance CreateInstanceReque
 	return instanceResponse, nil
 }
 
-func (api *SpheronApi) CloseClusterInstance(id string) (GenericResponse, error) {
+func (api *SpheronApi) CloseClusterInstance(ctx context.Context, id string) (GenericResponse, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/close", id)
 
-	responseBytes, err := api.sendApiRequest("POST", path, nil, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "POST", path, nil, nil)
 	if err != nil {
 		return GenericResponse{}, err
 	}
@@ -177,10 +253,44 @@ func (api *SpheronApi) CloseClusterInstance(id string) (GenericResponse, error)
 	return response, nil
 }
 
-func (api *SpheronApi) UpdateClusterInstance(id string, clusterInstance UpdateInstanceRequest) (InstanceResponse, error) {
+func (api *SpheronApi) StopClusterInstance(ctx context.Context, id string) (GenericResponse, error) {
+	path := fmt.Sprintf("/v1/cluster-instance/%s/stop", id)
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodPost, path, nil, nil)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	var response GenericResponse
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (api *SpheronApi) StartClusterInstance(ctx context.Context, id string, request StartInstanceRequest) (InstanceResponse, error) {
+	path := fmt.Sprintf("/v1/cluster-instance/%s/start", id)
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodPost, path, request, nil)
+	if err != nil {
+		return InstanceResponse{}, err
+	}
+
+	var response InstanceResponse
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return InstanceResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (api *SpheronApi) UpdateClusterInstance(ctx context.Context, id string, clusterInstance UpdateInstanceRequest) (InstanceResponse, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/update", id)
 
-	responseBytes, err := api.sendApiRequest("PATCH", path, clusterInstance, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "PATCH", path, clusterInstance, nil)
 	if err != nil {
 		return InstanceResponse{}, err
 	}
@@ -194,10 +304,44 @@ func (api *SpheronApi) UpdateClusterInstance(id string, clusterInstance UpdateIn
 	return response, nil
 }
 
-func (api *SpheronApi) UpdateClusterInstanceHealthCheckInfo(id string, healthCheck HealthCheckUpdateReq) (GenericResponse, error) {
+func (api *SpheronApi) UpdateClusterInstanceHealthCheckInfo(ctx context.Context, id string, healthCheck HealthCheckUpdateReq) (GenericResponse, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/update/health-check", id)
 
-	responseBytes, err := api.sendApiRequest("PATCH", path, healthCheck, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "PATCH", path, healthCheck, nil)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	var response GenericResponse
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (api *SpheronApi) ResizeClusterInstanceVolume(ctx context.Context, id string, name string, size string) (GenericResponse, error) {
+	path := fmt.Sprintf("/v1/cluster-instance/%s/volumes/%s", id, name)
+
+	responseBytes, err := api.sendApiRequest(ctx, "PATCH", path, ResizeVolumeRequest{Size: size}, nil)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	var response GenericResponse
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (api *SpheronApi) TopUpClusterInstanceEscrow(ctx context.Context, id string, amount int) (GenericResponse, error) {
+	path := fmt.Sprintf("/v1/cluster-instance/%s/escrow/deposit", id)
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodPost, path, EscrowTopUpRequest{Amount: amount}, nil)
 	if err != nil {
 		return GenericResponse{}, err
 	}
@@ -211,10 +355,27 @@ func (api *SpheronApi) UpdateClusterInstanceHealthCheckInfo(id string, healthChe
 	return response, nil
 }
 
-func (api *SpheronApi) GetClusterInstance(id string) (Instance, error) {
+func (api *SpheronApi) WithdrawClusterInstanceEscrow(ctx context.Context, id string) (GenericResponse, error) {
+	path := fmt.Sprintf("/v1/cluster-instance/%s/escrow/withdraw", id)
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodPost, path, nil, nil)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	var response GenericResponse
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return GenericResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (api *SpheronApi) GetClusterInstance(ctx context.Context, id string) (Instance, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s", id)
 
-	responseBytes, err := api.sendApiRequest("GET", path, nil, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "GET", path, nil, nil)
 	if err != nil {
 		return Instance{}, err
 	}
@@ -238,6 +399,9 @@ func (api *SpheronApi) WaitForDeployedEvent(ctx context.Context, topicID string)
 
 	req.Header.Set("Authorization", "Bearer "+api.token)
 
+	ctx = api.logContext(ctx)
+	tflog.SubsystemDebug(ctx, logSubsystem, "Subscribing to Spheron deployment events", map[string]interface{}{"topic": topicID})
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", err
@@ -248,6 +412,7 @@ func (api *SpheronApi) WaitForDeployedEvent(ctx context.Context, topicID string)
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
+			tflog.SubsystemDebug(ctx, logSubsystem, "Spheron deployment event stream closed", map[string]interface{}{"topic": topicID, "error": err.Error()})
 			return "", err
 		}
 
@@ -258,6 +423,11 @@ func (api *SpheronApi) WaitForDeployedEvent(ctx context.Context, topicID string)
 				return "", err
 			}
 
+			tflog.SubsystemDebug(ctx, logSubsystem, "Received Spheron deployment event", map[string]interface{}{
+				"topic": topicID,
+				"data":  redactBody([]byte(strings.TrimPrefix(strings.TrimSpace(data), "data:"))),
+			})
+
 			if strings.Contains(data, `"type":2`) {
 				return data, nil
 			}
@@ -269,10 +439,10 @@ func (api *SpheronApi) WaitForDeployedEvent(ctx context.Context, topicID string)
 	}
 }
 
-func (api *SpheronApi) AddClusterInstanceDomain(instanceID string, domain DomainRequest) (Domain, error) {
+func (api *SpheronApi) AddClusterInstanceDomain(ctx context.Context, instanceID string, domain DomainRequest) (Domain, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/domains", instanceID)
 
-	responseBytes, err := api.sendApiRequest("POST", path, domain, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "POST", path, domain, nil)
 	if err != nil {
 		return Domain{}, err
 	}
@@ -286,10 +456,10 @@ func (api *SpheronApi) AddClusterInstanceDomain(instanceID string, domain Domain
 	return response.Domain, nil
 }
 
-func (api *SpheronApi) UpdateClusterInstanceDomain(instanceID, domainID string, domain DomainRequest) (Domain, error) {
+func (api *SpheronApi) UpdateClusterInstanceDomain(ctx context.Context, instanceID, domainID string, domain DomainRequest) (Domain, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/domains/%s", instanceID, domainID)
 
-	responseBytes, err := api.sendApiRequest("PATCH", path, domain, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "PATCH", path, domain, nil)
 	if err != nil {
 		return Domain{}, err
 	}
@@ -303,10 +473,10 @@ func (api *SpheronApi) UpdateClusterInstanceDomain(instanceID, domainID string,
 	return response.Domain, nil
 }
 
-func (api *SpheronApi) DeleteClusterInstanceDomain(instanceID, domainID string) error {
+func (api *SpheronApi) DeleteClusterInstanceDomain(ctx context.Context, instanceID, domainID string) error {
 	path := fmt.Sprintf("/v1/cluster-instance/%s/domains/%s", instanceID, domainID)
 
-	_, err := api.sendApiRequest("DELETE", path, nil, nil)
+	_, err := api.sendApiRequest(ctx, "DELETE", path, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -314,10 +484,10 @@ func (api *SpheronApi) DeleteClusterInstanceDomain(instanceID, domainID string)
 	return nil
 }
 
-func (api *SpheronApi) GetClusterInstanceOrder(id string) (InstanceOrder, error) {
+func (api *SpheronApi) GetClusterInstanceOrder(ctx context.Context, id string) (InstanceOrder, error) {
 	path := fmt.Sprintf("/v1/cluster-instance/order/%s", id)
 
-	responseBytes, err := api.sendApiRequest("GET", path, nil, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "GET", path, nil, nil)
 	if err != nil {
 		return InstanceOrder{}, err
 	}
@@ -334,10 +504,10 @@ func (api *SpheronApi) GetClusterInstanceOrder(id string) (InstanceOrder, error)
 	return response.Order, nil
 }
 
-func (api *SpheronApi) CreateClusterInstanceFromTemplate(request CreateInstanceFromMarketplaceRequest) (InstanceResponse, error) {
+func (api *SpheronApi) CreateClusterInstanceFromTemplate(ctx context.Context, request CreateInstanceFromMarketplaceRequest) (InstanceResponse, error) {
 	path := "/v1/cluster-instance/template"
 
-	responseBytes, err := api.sendApiRequest("POST", path, request, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "POST", path, request, nil)
 	if err != nil {
 		return InstanceResponse{}, err
 	}
@@ -351,10 +521,17 @@ func (api *SpheronApi) CreateClusterInstanceFromTemplate(request CreateInstanceF
 	return response, nil
 }
 
-func (api *SpheronApi) GetClusterTemplates() ([]MarketplaceApp, error) {
+func (api *SpheronApi) GetClusterTemplates(ctx context.Context) ([]MarketplaceApp, error) {
+	api.catalogMutex.Lock()
+	defer api.catalogMutex.Unlock()
+
+	if api.clusterTemplates != nil {
+		return api.clusterTemplates, nil
+	}
+
 	path := "/v1/cluster-templates"
 
-	responseBytes, err := api.sendApiRequest("GET", path, nil, nil)
+	responseBytes, err := api.sendApiRequest(ctx, "GET", path, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -367,36 +544,121 @@ func (api *SpheronApi) GetClusterTemplates() ([]MarketplaceApp, error) {
 		return nil, err
 	}
 
-	return response.ClusterTemplates, nil
+	api.clusterTemplates = response.ClusterTemplates
+
+	return api.clusterTemplates, nil
 }
 
-func (api *SpheronApi) GetComputeMachines() ([]ComputeMachine, error) {
+func (api *SpheronApi) GetComputeMachines(ctx context.Context) ([]ComputeMachine, error) {
+	api.catalogMutex.Lock()
+	defer api.catalogMutex.Unlock()
+
+	if api.computeMachines != nil {
+		return api.computeMachines, nil
+	}
+
 	path := "/v1/compute-machine-image"
+	computeMachines := []ComputeMachine{}
 
-	requestOptions := map[string]interface{}{
-		"skip":  "0",
-		"limit": "10",
+	for {
+		requestOptions := map[string]interface{}{
+			"skip":  strconv.Itoa(len(computeMachines)),
+			"limit": "50",
+		}
+
+		responseBytes, err := api.sendApiRequest(ctx, "GET", path, nil, requestOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		var response struct {
+			AkashMachineImages []ComputeMachine `json:"akashMachineImages"`
+			TotalCount         int              `json:"totalCount"`
+		}
+		err = json.Unmarshal(responseBytes, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		computeMachines = append(computeMachines, response.AkashMachineImages...)
+
+		if len(response.AkashMachineImages) == 0 || len(computeMachines) >= response.TotalCount {
+			break
+		}
+	}
+
+	api.computeMachines = computeMachines
+
+	return api.computeMachines, nil
+}
+
+func (api *SpheronApi) GetRegions(ctx context.Context) ([]Region, error) {
+	api.catalogMutex.Lock()
+	defer api.catalogMutex.Unlock()
+
+	if api.regions != nil {
+		return api.regions, nil
 	}
 
-	responseBytes, err := api.sendApiRequest("GET", path, nil, requestOptions)
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodGet, "/v1/regions", nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	var response struct {
-		AkashMachineImages []ComputeMachine `json:"akashMachineImages"`
-		TotalCount         int              `json:"totalCount"`
+		Regions []Region `json:"regions"`
 	}
 	err = json.Unmarshal(responseBytes, &response)
 	if err != nil {
 		return nil, err
 	}
 
-	return response.AkashMachineImages, nil
+	api.regions = response.Regions
+
+	return api.regions, nil
+}
+
+func (api *SpheronApi) GetInstanceLimits(ctx context.Context) (InstanceLimits, error) {
+	api.catalogMutex.Lock()
+	defer api.catalogMutex.Unlock()
+
+	if api.instanceLimits != nil {
+		return *api.instanceLimits, nil
+	}
+
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodGet, "/v1/cluster-instance/limits", nil, nil)
+	if err != nil {
+		return InstanceLimits{}, err
+	}
+
+	var response InstanceLimits
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return InstanceLimits{}, err
+	}
+
+	api.instanceLimits = &response
+
+	return response, nil
+}
+
+func (api *SpheronApi) GetInstancePrice(ctx context.Context, request InstancePriceRequest) (InstancePrice, error) {
+	responseBytes, err := api.sendApiRequest(ctx, HttpMethodPost, "/v1/cluster-instance/price", request, nil)
+	if err != nil {
+		return InstancePrice{}, err
+	}
+
+	var response InstancePrice
+	err = json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return InstancePrice{}, err
+	}
+
+	return response, nil
 }
 
-func (api *SpheronApi) GetCluster(id string) (Cluster, error) {
-	response, err := api.sendApiRequest(HttpMethodGet, fmt.Sprintf("/v1/cluster/%s", id), nil, nil)
+func (api *SpheronApi) GetCluster(ctx context.Context, id string) (Cluster, error) {
+	response, err := api.sendApiRequest(ctx, HttpMethodGet, fmt.Sprintf("/v1/cluster/%s", id), nil, nil)
 	if err != nil {
 		return Cluster{}, err
 	}
@@ -411,8 +673,8 @@ func (api *SpheronApi) GetCluster(id string) (Cluster, error) {
 	return responseWrapper.Cluster, nil
 }
 
-func (api *SpheronApi) GetClusterInstanceDomains(id string) ([]Domain, error) {
-	response, err := api.sendApiRequest(HttpMethodGet, fmt.Sprintf("/v1/cluster-instance/%s/domains", id), nil, nil)
+func (api *SpheronApi) GetClusterInstanceDomains(ctx context.Context, id string) ([]Domain, error) {
+	response, err := api.sendApiRequest(ctx, HttpMethodGet, fmt.Sprintf("/v1/cluster-instance/%s/domains", id), nil, nil)
 	if err != nil {
 		return []Domain{}, err
 	}
@@ -0,0 +1,100 @@
+package fake
+
+import "terraform-provider-spheron/internal/client"
+
+const (
+	DefaultToken         = "fake-spheron-token"
+	DefaultProviderHost  = "provider.fake.spheron.network"
+	DefaultEscrowDeposit = 5000000
+)
+
+type template struct {
+	app   client.MarketplaceApp
+	ports []client.Port
+}
+
+func defaultUser() client.User {
+	return client.User{
+		ID:       "user-1",
+		Username: "terraform",
+		Name:     "Terraform",
+		Email:    "terraform@spheron.network",
+	}
+}
+
+func defaultTokenPermissions() []string {
+	return []string{"read", "deploy"}
+}
+
+func defaultOrganization() client.Organization {
+	organization := client.Organization{ID: "org-1"}
+	organization.Profile.Name = "Terraform Organization"
+	organization.Profile.Username = "terraform"
+
+	return organization
+}
+
+func defaultTemplates() []template {
+	return []template{
+		{
+			app: client.MarketplaceApp{
+				ID:   "template-postgres",
+				Name: "Postgres",
+				ServiceData: client.MarketplaceAppServiceData{
+					DockerImage:    "postgres",
+					DockerImageTag: "15",
+					Versions:       []string{"14", "15", "16"},
+					Variables: []client.MarketplaceAppVariable{
+						{Name: "POSTGRES_PASSWORD", Label: "Password", Required: true},
+						{Name: "POSTGRES_USER", Label: "User", DefaultValue: "postgres"},
+						{Name: "POSTGRES_DB", Label: "Database", DefaultValue: "postgres"},
+					},
+				},
+			},
+			ports: []client.Port{{ContainerPort: 5432}},
+		},
+		{
+			app: client.MarketplaceApp{
+				ID:   "template-ipfs",
+				Name: "IPFS",
+				ServiceData: client.MarketplaceAppServiceData{
+					DockerImage:    "ipfs/kubo",
+					DockerImageTag: "latest",
+				},
+			},
+			ports: []client.Port{{ContainerPort: 5001}, {ContainerPort: 8080, ExposedPort: 80}},
+		},
+	}
+}
+
+func defaultMachines() []client.ComputeMachine {
+	return []client.ComputeMachine{
+		{ID: "machine-ventus-nano", Name: "Ventus Nano", Cpu: 1, Memory: "0.5Gi"},
+		{ID: "machine-ventus-small", Name: "Ventus Small", Cpu: 1, Memory: "2Gi"},
+		{ID: "machine-terra-small", Name: "Terra Small", Cpu: 4, Memory: "4Gi"},
+	}
+}
+
+func defaultRegions() []client.Region {
+	return []client.Region{
+		{Name: "us-east", Capacity: client.RegionCapacity{Cpu: 64, Memory: 256, Storage: 4096}, Pricing: defaultRegionPricing(), Gpus: []client.RegionGpu{
+			{Vendor: "nvidia", Model: "a100", Vram: "40Gi", Available: 8, PricePerHour: 1.1},
+			{Vendor: "nvidia", Model: "a100", Vram: "80Gi", Available: 2, PricePerHour: 1.6},
+			{Vendor: "nvidia", Model: "rtx4090", Vram: "24Gi", Available: 16, PricePerHour: 0.35},
+		}},
+		{Name: "us-west", Capacity: client.RegionCapacity{Cpu: 32, Memory: 128, Storage: 2048}, Pricing: defaultRegionPricing(), Gpus: []client.RegionGpu{
+			{Vendor: "nvidia", Model: "rtx4090", Vram: "24Gi", Available: 4, PricePerHour: 0.4},
+			{Vendor: "amd", Model: "mi300x", Vram: "192Gi", Available: 1, PricePerHour: 2.5},
+		}},
+		{Name: "us-central", Capacity: client.RegionCapacity{Cpu: 16, Memory: 64, Storage: 1024}, Pricing: defaultRegionPricing()},
+		{Name: "eu-west", Capacity: client.RegionCapacity{Cpu: 2, Memory: 4, Storage: 100}, Pricing: defaultRegionPricing()},
+	}
+}
+
+func defaultRegionPricing() client.RegionPricing {
+	return client.RegionPricing{CpuPerHour: 0.012, MemoryPerHour: 0.004, StoragePerHour: 0.0002}
+}
+
+func defaultInstanceLimits() client.InstanceLimits {
+	return client.InstanceLimits{MinCpu: 0.1, MaxCpu: 32, MinMemory: 0.125, MaxMemory: 64}
+}
@@ -0,0 +1,1092 @@
+// Package fake implements an in-memory Spheron API used by the provider tests.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+)
+
+type Server struct {
+	*httptest.Server
+
+	Token string
+
+	// AutoVerifyDomains marks every domain as verified as soon as it is added.
+	AutoVerifyDomains bool
+
+	// UnhealthyTags lists image tags whose deployments fail the instance health check.
+	UnhealthyTags []string
+
+	// FailingTags lists image tags whose new instances fail to deploy.
+	FailingTags []string
+
+	// TokenPermissions and TokenExpiresAt are reported in the token scope. Requests made after
+	// TokenExpiresAt are rejected.
+	TokenPermissions []string
+	TokenExpiresAt   time.Time
+
+	mu           sync.Mutex
+	nextID       int
+	nextPort     int
+	user         client.User
+	organization client.Organization
+	templates    []template
+	machines     []client.ComputeMachine
+	regions      []client.Region
+	limits       client.InstanceLimits
+	clusters     map[string]*client.Cluster
+	instances    map[string]*client.Instance
+	orders       map[string]*client.InstanceOrder
+	domains      map[string][]client.Domain
+	topics       map[string]string
+}
+
+func NewServer() *Server {
+	s := &Server{
+		Token:            DefaultToken,
+		TokenPermissions: defaultTokenPermissions(),
+		nextPort:         30000,
+		user:             defaultUser(),
+		organization:     defaultOrganization(),
+		templates:        defaultTemplates(),
+		machines:         defaultMachines(),
+		regions:          defaultRegions(),
+		limits:           defaultInstanceLimits(),
+		clusters:         map[string]*client.Cluster{},
+		instances:        map[string]*client.Instance{},
+		orders:           map[string]*client.InstanceOrder{},
+		domains:          map[string][]client.Domain{},
+		topics:           map[string]string{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+func (s *Server) Instance(id string) (client.Instance, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.instances[id]
+	if !ok {
+		return client.Instance{}, false
+	}
+	return *instance, true
+}
+
+func (s *Server) Order(id string) (client.InstanceOrder, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[id]
+	if !ok {
+		return client.InstanceOrder{}, false
+	}
+	return *order, true
+}
+
+func (s *Server) Domains(instanceID string) []client.Domain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]client.Domain{}, s.domains[instanceID]...)
+}
+
+// CloseInstance closes the instance out of band, as if it was closed from the Spheron console.
+func (s *Server) CloseInstance(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if instance, ok := s.instances[id]; ok {
+		instance.State = "Closed"
+	}
+}
+
+// MoveInstance changes the provider host of the active order, as if the instance was redeployed to another provider.
+func (s *Server) MoveInstance(id string, providerHost string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.instances[id]
+	if !ok {
+		return
+	}
+
+	if order, ok := s.orders[instance.ActiveOrder]; ok {
+		order.ProtocolData = &client.ProtocolData{ProviderHost: providerHost}
+	}
+}
+
+// AddInstance deploys an instance out of band, as if it was created from the Spheron console.
+func (s *Server) AddInstance(clusterName string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := s.deploy(clusterName, s.newID("topic"), client.HealthCheck{}, client.ClusterInstanceConfiguration{
+		Image:         "crccheck/hello-world",
+		Tag:           "latest",
+		Ports:         []client.Port{{ContainerPort: 8000}},
+		Region:        "us-east",
+		InstanceCount: 1,
+		AgreedMachineImage: client.MachineImageType{
+			MachineType: "Custom Plan",
+			Cpu:         1,
+			Memory:      "2Gi",
+			Storage:     "10Gi",
+		},
+	})
+
+	return response.ClusterInstanceID
+}
+
+// AddDomain attaches a domain to the instance out of band, as if it was added from the Spheron console.
+func (s *Server) AddDomain(instanceID string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.domains[instanceID] = append(s.domains[instanceID], client.Domain{
+		ID:         s.newID("domain"),
+		Name:       name,
+		Type:       client.DomainTypeDomain,
+		InstanceID: instanceID,
+	})
+}
+
+func (s *Server) VerifyDomain(instanceID string, domainID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.domains[instanceID] {
+		if s.domains[instanceID][i].ID == domainID {
+			s.domains[instanceID][i].Verified = true
+		}
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if !s.TokenExpiresAt.IsZero() && s.TokenExpiresAt.Before(time.Now()) {
+		writeError(w, http.StatusUnauthorized, "Token expired")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	route := r.Method + " " + routePattern(segments)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch route {
+	case "GET v1/api-keys/scope":
+		s.getTokenScope(w)
+	case "GET v1/organization/:id":
+		s.getOrganization(w, segments[2])
+	case "GET v1/organization/:id/cluster-instances":
+		s.getActiveInstances(w, segments[2])
+	case "POST v1/cluster-instance/create":
+		s.createInstance(w, r)
+	case "POST v1/cluster-instance/template":
+		s.createInstanceFromTemplate(w, r)
+	case "POST v1/cluster-instance/price":
+		s.getInstancePrice(w, r)
+	case "GET v1/cluster-instance/limits":
+		s.getInstanceLimits(w)
+	case "GET v1/cluster-instance/:id":
+		s.getInstance(w, segments[2])
+	case "PATCH v1/cluster-instance/:id/update":
+		s.updateInstance(w, r, segments[2])
+	case "PATCH v1/cluster-instance/:id/update/health-check":
+		s.updateInstanceHealthCheck(w, r, segments[2])
+	case "PATCH v1/cluster-instance/:id/volumes/:id":
+		s.resizeVolume(w, r, segments[2], segments[4])
+	case "POST v1/cluster-instance/:id/close":
+		s.closeInstance(w, segments[2])
+	case "POST v1/cluster-instance/:id/stop":
+		s.stopInstance(w, segments[2])
+	case "POST v1/cluster-instance/:id/start":
+		s.startInstance(w, r, segments[2])
+	case "POST v1/cluster-instance/:id/escrow/deposit":
+		s.topUpEscrow(w, r, segments[2])
+	case "POST v1/cluster-instance/:id/escrow/withdraw":
+		s.withdrawEscrow(w, segments[2])
+	case "GET v1/cluster-instance/order/:id":
+		s.getOrder(w, segments[3])
+	case "GET v1/cluster-instance/:id/domains":
+		s.getDomains(w, segments[2])
+	case "POST v1/cluster-instance/:id/domains":
+		s.addDomain(w, r, segments[2])
+	case "PATCH v1/cluster-instance/:id/domains/:id":
+		s.updateDomain(w, r, segments[2], segments[4])
+	case "DELETE v1/cluster-instance/:id/domains/:id":
+		s.deleteDomain(w, segments[2], segments[4])
+	case "GET v1/cluster-templates":
+		s.getTemplates(w)
+	case "GET v1/compute-machine-image":
+		s.getComputeMachines(w, r)
+	case "GET v1/regions":
+		s.getRegions(w)
+	case "GET v1/cluster/:id":
+		s.getCluster(w, segments[2])
+	case "GET v1/subscribe":
+		s.subscribe(w, r.URL.Query().Get("sessionId"))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Route %s %s not found", r.Method, r.URL.Path))
+	}
+}
+
+// routePattern replaces dynamic path segments with ":id" so routes can be matched with a switch.
+func routePattern(segments []string) string {
+	static := map[string]bool{
+		"v1": true, "api-keys": true, "scope": true, "organization": true, "cluster-instance": true, "cluster-instances": true,
+		"create": true, "template": true, "update": true, "health-check": true, "close": true,
+		"order": true, "domains": true, "cluster-templates": true, "compute-machine-image": true,
+		"cluster": true, "subscribe": true, "regions": true, "price": true, "limits": true, "volumes": true,
+		"escrow": true, "deposit": true, "withdraw": true, "stop": true, "start": true,
+	}
+
+	pattern := make([]string, len(segments))
+	for i, segment := range segments {
+		if static[segment] {
+			pattern[i] = segment
+		} else {
+			pattern[i] = ":id"
+		}
+	}
+
+	return strings.Join(pattern, "/")
+}
+
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func (s *Server) getTokenScope(w http.ResponseWriter) {
+	scope := client.TokenScope{
+		User: s.user,
+		Organizations: []client.TokenOrganization{
+			{
+				ID:       s.organization.ID,
+				Name:     s.organization.Profile.Name,
+				Username: s.organization.Profile.Username,
+			},
+		},
+		Permissions: s.TokenPermissions,
+	}
+
+	if !s.TokenExpiresAt.IsZero() {
+		scope.ExpiresAt = &s.TokenExpiresAt
+	}
+
+	writeJSON(w, scope)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, id string) {
+	if id != s.organization.ID {
+		writeError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	writeJSON(w, s.organization)
+}
+
+func (s *Server) getActiveInstances(w http.ResponseWriter, id string) {
+	if id != s.organization.ID {
+		writeError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	instances := []client.ActiveInstance{}
+	for _, instance := range s.instances {
+		if instance.State != "Active" {
+			continue
+		}
+
+		active := client.ActiveInstance{ID: instance.ID, Name: instance.Name}
+		if order, ok := s.orders[instance.ActiveOrder]; ok {
+			config := order.ClusterInstanceConfiguration
+			machineImage := config.AgreedMachineImage
+			active.PricePerHour, _ = s.pricePerHour(config.Region, float64(machineImage.Cpu), machineImage.Memory, machineImage.Storage, machineImage.GetVolumes(), machineImage.Gpu)
+			active.PricePerHour *= float64(config.InstanceCount)
+		}
+		instances = append(instances, active)
+	}
+
+	writeJSON(w, map[string]interface{}{"instances": instances})
+}
+
+func (s *Server) createInstance(w http.ResponseWriter, r *http.Request) {
+	var request client.CreateInstanceRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.OrganizationID != s.organization.ID {
+		writeError(w, http.StatusForbidden, "Organization not found")
+		return
+	}
+
+	config := request.Configuration
+
+	machineImage := client.MachineImageType{
+		MachineType:       "Custom Plan",
+		Storage:           config.CustomInstanceSpecs.Storage,
+		Memory:            config.CustomInstanceSpecs.Memory,
+		PersistentStorage: config.CustomInstanceSpecs.PersistentStorage,
+		Volumes:           config.CustomInstanceSpecs.Volumes,
+	}
+
+	if config.AkashMachineImageName != "" {
+		machine, ok := s.findMachineByName(config.AkashMachineImageName)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Machine image not found")
+			return
+		}
+
+		machineImage.MachineType = machine.Name
+		machineImage.Cpu = machine.Cpu
+		machineImage.Memory = machine.Memory
+	} else {
+		cpu, err := strconv.ParseFloat(config.CustomInstanceSpecs.CPU, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid cpu value")
+			return
+		}
+		machineImage.Cpu = float32(cpu)
+	}
+
+	if config.CustomInstanceSpecs.Gpu != nil {
+		gpu, ok := s.findGpu(config.Region, *config.CustomInstanceSpecs.Gpu)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "GPU model not available")
+			return
+		}
+
+		machineImage.Gpu = &client.GpuSpecs{Count: config.CustomInstanceSpecs.Gpu.Count, Vendor: gpu.Vendor, Model: gpu.Model, Vram: gpu.Vram}
+	}
+
+	healthCheck := client.HealthCheck{URL: request.HealthCheckURL}
+	if port, err := strconv.Atoi(request.HealthCheckPort); err == nil {
+		healthCheck.Port = client.Port{ContainerPort: port}
+	}
+
+	response := s.deploy(request.ClusterName, request.UniqueTopicID, healthCheck, client.ClusterInstanceConfiguration{
+		Image:              config.Image,
+		Tag:                config.Tag,
+		Ports:              config.Ports,
+		Env:                config.Env,
+		Command:            config.Command,
+		Args:               config.Args,
+		Region:             config.Region,
+		AgreedMachineImage: machineImage,
+		InstanceCount:      config.InstanceCount,
+	})
+
+	writeJSON(w, response)
+}
+
+func (s *Server) createInstanceFromTemplate(w http.ResponseWriter, r *http.Request) {
+	var request client.CreateInstanceFromMarketplaceRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	var chosen *template
+	for i := range s.templates {
+		if s.templates[i].app.ID == request.TemplateID {
+			chosen = &s.templates[i]
+		}
+	}
+
+	if chosen == nil {
+		writeError(w, http.StatusNotFound, "Template not found")
+		return
+	}
+
+	tag := chosen.app.ServiceData.DockerImageTag
+	if request.Tag != "" {
+		tag = request.Tag
+	}
+
+	env := make([]client.Env, 0, len(request.EnvironmentVariables))
+	for _, variable := range request.EnvironmentVariables {
+		for _, appVariable := range chosen.app.ServiceData.Variables {
+			if appVariable.Label == variable.Label {
+				env = append(env, client.Env{Value: appVariable.Name + "=" + variable.Value})
+			}
+		}
+	}
+
+	machineImage := client.MachineImageType{
+		MachineType:       "Custom Plan",
+		Storage:           request.CustomInstanceSpecs.Storage,
+		Memory:            request.CustomInstanceSpecs.Memory,
+		PersistentStorage: request.CustomInstanceSpecs.PersistentStorage,
+		Volumes:           request.CustomInstanceSpecs.Volumes,
+	}
+
+	if request.AkashImageID != "" {
+		machine, ok := s.findMachineByID(request.AkashImageID)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Machine image not found")
+			return
+		}
+
+		machineImage.MachineType = machine.Name
+		machineImage.Cpu = machine.Cpu
+		machineImage.Memory = machine.Memory
+	} else {
+		cpu, err := strconv.ParseFloat(request.CustomInstanceSpecs.CPU, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid cpu value")
+			return
+		}
+		machineImage.Cpu = float32(cpu)
+	}
+
+	if request.CustomInstanceSpecs.Gpu != nil {
+		gpu, ok := s.findGpu(request.Region, *request.CustomInstanceSpecs.Gpu)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "GPU model not available")
+			return
+		}
+
+		machineImage.Gpu = &client.GpuSpecs{Count: request.CustomInstanceSpecs.Gpu.Count, Vendor: gpu.Vendor, Model: gpu.Model, Vram: gpu.Vram}
+	}
+
+	response := s.deploy(chosen.app.Name, request.UniqueTopicID, client.HealthCheck{}, client.ClusterInstanceConfiguration{
+		Image:              chosen.app.ServiceData.DockerImage,
+		Tag:                tag,
+		Ports:              chosen.ports,
+		Env:                env,
+		Region:             request.Region,
+		AgreedMachineImage: machineImage,
+		InstanceCount:      request.InstanceCount,
+	})
+
+	writeJSON(w, response)
+}
+
+func (s *Server) deploy(clusterName string, topic string, healthCheck client.HealthCheck, config client.ClusterInstanceConfiguration) client.InstanceResponse {
+	var cluster *client.Cluster
+	for _, c := range s.clusters {
+		if c.Name == clusterName {
+			cluster = c
+		}
+	}
+
+	if cluster == nil {
+		id := s.newID("cluster")
+		cluster = &client.Cluster{ID: id, Name: clusterName, URL: config.Image}
+		s.clusters[id] = cluster
+	}
+
+	ports := make([]client.Port, 0, len(config.Ports))
+	for _, port := range config.Ports {
+		if port.ExposedPort != 80 {
+			s.nextPort++
+			port.ExposedPort = s.nextPort
+		}
+		ports = append(ports, port)
+	}
+	config.Ports = ports
+
+	instance := &client.Instance{
+		ID:             s.newID("instance"),
+		State:          "Active",
+		Name:           clusterName,
+		RetrievableAkt: DefaultEscrowDeposit,
+		Cluster:        cluster.ID,
+		HealthCheck:    healthCheck,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	s.instances[instance.ID] = instance
+
+	order := s.newOrder(instance, config)
+	s.topics[topic] = order.ID
+	for _, tag := range s.FailingTags {
+		if tag == config.Tag {
+			order.Status = "Failed"
+			delete(s.topics, topic)
+		}
+	}
+
+	return client.InstanceResponse{
+		ClusterID:              cluster.ID,
+		ClusterInstanceID:      instance.ID,
+		ClusterInstanceOrderID: order.ID,
+		Topic:                  topic,
+	}
+}
+
+func (s *Server) newOrder(instance *client.Instance, config client.ClusterInstanceConfiguration) *client.InstanceOrder {
+	order := &client.InstanceOrder{
+		ID:                           s.newID("order"),
+		Status:                       "Deployed",
+		ProtocolData:                 &client.ProtocolData{ProviderHost: DefaultProviderHost},
+		ClusterInstanceConfiguration: &config,
+		CreatedAt:                    time.Now(),
+	}
+
+	for _, port := range config.Ports {
+		if port.ExposedPort == 80 {
+			order.URLPreview = fmt.Sprintf("%s.%s", instance.ID, DefaultProviderHost)
+		}
+	}
+
+	s.orders[order.ID] = order
+	instance.Orders = append(instance.Orders, order.ID)
+	instance.ActiveOrder = order.ID
+	instance.LatestURLPreview = order.URLPreview
+	instance.UpdatedAt = time.Now()
+
+	if instance.HealthCheck.URL != "" {
+		instance.HealthCheck.Status = "Healthy"
+		for _, tag := range s.UnhealthyTags {
+			if tag == config.Tag {
+				instance.HealthCheck.Status = "Unhealthy"
+			}
+		}
+		instance.HealthCheck.Timestamp = order.CreatedAt
+	}
+
+	return order
+}
+
+func (s *Server) getInstance(w http.ResponseWriter, id string) {
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	writeJSON(w, client.GetClusterInstanceResponse{Success: true, Instance: *instance})
+}
+
+func (s *Server) updateInstance(w http.ResponseWriter, r *http.Request, id string) {
+	var request client.UpdateInstanceRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	if instance.State == "Closed" {
+		writeError(w, http.StatusBadRequest, "Instance is closed")
+		return
+	}
+
+	config := *s.orders[instance.ActiveOrder].ClusterInstanceConfiguration
+	config.Env = request.Env
+	config.Command = request.Command
+	config.Args = request.Args
+	config.Tag = request.Tag
+
+	order := s.newOrder(instance, config)
+	s.topics[request.UniqueTopicID] = order.ID
+
+	writeJSON(w, client.InstanceResponse{
+		ClusterID:              instance.Cluster,
+		ClusterInstanceID:      instance.ID,
+		ClusterInstanceOrderID: order.ID,
+		Topic:                  request.UniqueTopicID,
+	})
+}
+
+func (s *Server) updateInstanceHealthCheck(w http.ResponseWriter, r *http.Request, id string) {
+	var request client.HealthCheckUpdateReq
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	instance.HealthCheck = client.HealthCheck{
+		URL:  request.HealthCheckURL,
+		Port: client.Port{ContainerPort: request.HealthCheckPort},
+	}
+
+	writeJSON(w, client.GenericResponse{Message: "Health check updated", Success: true, Updated: true})
+}
+
+func (s *Server) resizeVolume(w http.ResponseWriter, r *http.Request, id string, name string) {
+	var request client.ResizeVolumeRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	config := s.orders[instance.ActiveOrder].ClusterInstanceConfiguration
+	volumes := append([]client.PersistentStorage(nil), config.AgreedMachineImage.Volumes...)
+
+	for i := range volumes {
+		if volumes[i].Name != name {
+			continue
+		}
+
+		if parseGi(request.Size) < parseGi(volumes[i].Size) {
+			writeError(w, http.StatusBadRequest, "Volumes can't be shrunk")
+			return
+		}
+
+		volumes[i].Size = request.Size
+		config.AgreedMachineImage.Volumes = volumes
+
+		writeJSON(w, client.GenericResponse{Message: "Volume resized", Success: true, Updated: true})
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "Volume not found")
+}
+
+func (s *Server) closeInstance(w http.ResponseWriter, id string) {
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	if instance.State == "Closed" {
+		writeError(w, http.StatusBadRequest, "Instance already closed")
+		return
+	}
+
+	instance.State = "Closed"
+
+	writeJSON(w, client.GenericResponse{Message: "Instance closed", Success: true})
+}
+
+func (s *Server) stopInstance(w http.ResponseWriter, id string) {
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	if instance.State != "Active" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Instance is %s", instance.State))
+		return
+	}
+
+	instance.State = "Stopped"
+	instance.UpdatedAt = time.Now()
+
+	writeJSON(w, client.GenericResponse{Message: "Instance stopped", Success: true})
+}
+
+func (s *Server) startInstance(w http.ResponseWriter, r *http.Request, id string) {
+	var request client.StartInstanceRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	if instance.State != "Stopped" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Instance is %s", instance.State))
+		return
+	}
+
+	instance.State = "Active"
+	order := s.newOrder(instance, *s.orders[instance.ActiveOrder].ClusterInstanceConfiguration)
+	s.topics[request.UniqueTopicID] = order.ID
+
+	writeJSON(w, client.InstanceResponse{
+		ClusterID:              instance.Cluster,
+		ClusterInstanceID:      instance.ID,
+		ClusterInstanceOrderID: order.ID,
+		Topic:                  request.UniqueTopicID,
+	})
+}
+
+func (s *Server) topUpEscrow(w http.ResponseWriter, r *http.Request, id string) {
+	var request client.EscrowTopUpRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	if instance.State == "Closed" {
+		writeError(w, http.StatusBadRequest, "Instance is closed")
+		return
+	}
+
+	if request.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, "Invalid amount")
+		return
+	}
+
+	instance.RetrievableAkt += request.Amount
+
+	writeJSON(w, client.GenericResponse{Message: "Escrow topped up", Success: true})
+}
+
+func (s *Server) withdrawEscrow(w http.ResponseWriter, id string) {
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	instance.WithdrawnAkt += instance.RetrievableAkt
+	instance.RetrievableAkt = 0
+
+	writeJSON(w, client.GenericResponse{Message: "Escrow withdrawn", Success: true})
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, id string) {
+	order, ok := s.orders[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Order not found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"order":    order,
+		"liveLogs": []string{},
+	})
+}
+
+func (s *Server) getDomains(w http.ResponseWriter, instanceID string) {
+	if _, ok := s.instances[instanceID]; !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"domains": append([]client.Domain{}, s.domains[instanceID]...)})
+}
+
+func (s *Server) addDomain(w http.ResponseWriter, r *http.Request, instanceID string) {
+	var request client.DomainRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if _, ok := s.instances[instanceID]; !ok {
+		writeError(w, http.StatusNotFound, "Instance not found")
+		return
+	}
+
+	for _, domain := range s.domains[instanceID] {
+		if domain.Name == request.Name {
+			writeError(w, http.StatusBadRequest, "Domain already exists")
+			return
+		}
+	}
+
+	domain := client.Domain{
+		ID:         s.newID("domain"),
+		Name:       request.Name,
+		Link:       request.Link,
+		Type:       request.Type,
+		InstanceID: instanceID,
+		Verified:   s.AutoVerifyDomains,
+	}
+	domain.DNSRecords = domainDNSRecords(domain)
+	s.domains[instanceID] = append(s.domains[instanceID], domain)
+
+	writeJSON(w, client.DomainResponse{Domain: domain})
+}
+
+func (s *Server) updateDomain(w http.ResponseWriter, r *http.Request, instanceID string, domainID string) {
+	var request client.DomainRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	for i, domain := range s.domains[instanceID] {
+		if domain.ID == domainID {
+			domain.Name = request.Name
+			domain.Link = request.Link
+			domain.Type = request.Type
+			domain.DNSRecords = domainDNSRecords(domain)
+			s.domains[instanceID][i] = domain
+
+			writeJSON(w, client.DomainResponse{Domain: domain})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Domain not found")
+}
+
+// domainDNSRecords returns the records the API asks users to create: a record pointing at the instance and a TXT challenge.
+func domainDNSRecords(domain client.Domain) []client.DomainDNSRecord {
+	if domain.Type == client.DomainTypeEns || domain.Type == client.DomainTypeHns {
+		return nil
+	}
+
+	records := []client.DomainDNSRecord{}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(domain.Link, "https://"), "http://")
+	host = strings.SplitN(host, ":", 2)[0]
+	if host != "" {
+		recordType := "CNAME"
+		if domain.Type == client.DomainTypeApex {
+			recordType = "ALIAS"
+		}
+		records = append(records, client.DomainDNSRecord{Type: recordType, Name: domain.Name, Value: host})
+	}
+
+	return append(records, client.DomainDNSRecord{
+		Type:  "TXT",
+		Name:  "_spheron-challenge." + strings.TrimPrefix(domain.Name, "*."),
+		Value: "spheron-verification=" + domain.ID,
+	})
+}
+
+func (s *Server) deleteDomain(w http.ResponseWriter, instanceID string, domainID string) {
+	for i, domain := range s.domains[instanceID] {
+		if domain.ID == domainID {
+			s.domains[instanceID] = append(s.domains[instanceID][:i], s.domains[instanceID][i+1:]...)
+
+			writeJSON(w, client.GenericResponse{Message: "Domain deleted", Success: true})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Domain not found")
+}
+
+func (s *Server) getTemplates(w http.ResponseWriter) {
+	apps := make([]client.MarketplaceApp, 0, len(s.templates))
+	for _, template := range s.templates {
+		apps = append(apps, template.app)
+	}
+
+	writeJSON(w, map[string]interface{}{"clusterTemplates": apps})
+}
+
+func (s *Server) getComputeMachines(w http.ResponseWriter, r *http.Request) {
+	skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = len(s.machines)
+	}
+
+	machines := make([]client.ComputeMachine, 0, len(s.machines))
+	for i, machine := range s.machines {
+		if i >= skip && i < skip+limit {
+			machines = append(machines, machine)
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"akashMachineImages": machines,
+		"totalCount":         len(s.machines),
+	})
+}
+
+func (s *Server) getRegions(w http.ResponseWriter) {
+	writeJSON(w, map[string]interface{}{"regions": s.regions})
+}
+
+func (s *Server) getInstanceLimits(w http.ResponseWriter) {
+	writeJSON(w, s.limits)
+}
+
+func (s *Server) getInstancePrice(w http.ResponseWriter, r *http.Request) {
+	var request client.InstancePriceRequest
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	cpu, _ := strconv.ParseFloat(request.CustomInstanceSpecs.CPU, 64)
+	memory := request.CustomInstanceSpecs.Memory
+
+	if request.AkashMachineImageName != "" {
+		machine, ok := s.findMachineByName(request.AkashMachineImageName)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "Machine image not found")
+			return
+		}
+
+		cpu = float64(machine.Cpu)
+		memory = machine.Memory
+	}
+
+	specs := request.CustomInstanceSpecs
+	pricePerHour, message := s.pricePerHour(request.Region, cpu, memory, specs.Storage, specs.GetVolumes(), specs.Gpu)
+	if message != "" {
+		writeError(w, http.StatusBadRequest, message)
+		return
+	}
+	pricePerHour *= float64(request.InstanceCount)
+	if request.Scalable {
+		pricePerHour *= 1.5
+	}
+
+	writeJSON(w, client.InstancePrice{
+		PricePerHour:  pricePerHour,
+		PricePerMonth: pricePerHour * 24 * 30,
+	})
+}
+
+// pricePerHour prices a single replica, returning an error message when the region or GPU is not available.
+func (s *Server) pricePerHour(regionName string, cpu float64, memory string, storage string, volumes []client.PersistentStorage, gpuSpecs *client.GpuSpecs) (float64, string) {
+	var region *client.Region
+	for i := range s.regions {
+		if s.regions[i].Name == regionName || (regionName == "any" && region == nil) {
+			region = &s.regions[i]
+		}
+	}
+
+	if region == nil {
+		return 0, "Region not found"
+	}
+
+	storageGi := parseGi(storage)
+	for _, volume := range volumes {
+		storageGi += parseGi(volume.Size)
+	}
+
+	price := cpu*region.Pricing.CpuPerHour + parseGi(memory)*region.Pricing.MemoryPerHour + storageGi*region.Pricing.StoragePerHour
+	if gpuSpecs != nil {
+		gpu, ok := s.findGpu(regionName, *gpuSpecs)
+		if !ok {
+			return 0, "GPU model not available"
+		}
+
+		price += gpu.PricePerHour * float64(gpuSpecs.Count)
+	}
+
+	return price, ""
+}
+
+func (s *Server) getCluster(w http.ResponseWriter, id string) {
+	cluster, ok := s.clusters[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Cluster not found")
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"cluster": cluster})
+}
+
+func (s *Server) subscribe(w http.ResponseWriter, topic string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+
+	event := map[string]interface{}{"type": 3, "session": topic}
+
+	if orderID, ok := s.topics[topic]; ok {
+		order := s.orders[orderID]
+		event = map[string]interface{}{
+			"type":    2,
+			"session": topic,
+			"data": map[string]interface{}{
+				"deploymentStatus": order.Status,
+				"latestUrlPreview": order.URLPreview,
+				"providerHost":     order.ProtocolData.ProviderHost,
+				"ports":            order.ClusterInstanceConfiguration.Ports,
+			},
+		}
+	}
+
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (s *Server) findMachineByName(name string) (client.ComputeMachine, bool) {
+	for _, m := range s.machines {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return client.ComputeMachine{}, false
+}
+
+func (s *Server) findGpu(region string, spec client.GpuSpecs) (client.RegionGpu, bool) {
+	for _, r := range s.regions {
+		if region != "any" && r.Name != region {
+			continue
+		}
+
+		for _, gpu := range r.Gpus {
+			if gpu.Model == spec.Model && (spec.Vendor == "" || gpu.Vendor == spec.Vendor) && (spec.Vram == "" || gpu.Vram == spec.Vram) {
+				return gpu, true
+			}
+		}
+	}
+	return client.RegionGpu{}, false
+}
+
+func (s *Server) findMachineByID(id string) (client.ComputeMachine, bool) {
+	for _, m := range s.machines {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return client.ComputeMachine{}, false
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(client.GenericResponse{Message: message})
+}
+
+func parseGi(value string) float64 {
+	number, _ := strconv.ParseFloat(strings.TrimSuffix(value, "Gi"), 64)
+	return number
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	logSubsystem    = "spheron_client"
+	requestIDHeader = "X-Request-Id"
+	redactedValue   = "***"
+)
+
+// String values of keys containing one of these words are never written to the logs.
+var sensitiveBodyKeys = []string{"token", "password", "secret", "authorization"}
+
+type sensitiveValuesKey struct{}
+
+// WithSensitiveValues returns a context in which the values are masked in the spheron_client logs.
+// Masks can't be set on the subsystem directly, since it is created again for every request.
+func WithSensitiveValues(ctx context.Context, values ...string) context.Context {
+	existing, _ := ctx.Value(sensitiveValuesKey{}).([]string)
+	merged := make([]string, 0, len(existing)+len(values))
+	merged = append(merged, existing...)
+	for _, value := range values {
+		if value != "" {
+			merged = append(merged, value)
+		}
+	}
+
+	return context.WithValue(ctx, sensitiveValuesKey{}, merged)
+}
+
+// logContext returns a context with the spheron_client subsystem logger. Its level can be set with
+// TF_LOG_PROVIDER_SPHERON_CLIENT.
+func (api *SpheronApi) logContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_SPHERON", "CLIENT"))
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, logSubsystem, "Authorization", "token")
+	if api.token != "" {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, logSubsystem, api.token)
+	}
+	if values, _ := ctx.Value(sensitiveValuesKey{}).([]string); len(values) != 0 {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, logSubsystem, values...)
+		ctx = tflog.SubsystemMaskMessageStrings(ctx, logSubsystem, values...)
+	}
+
+	return ctx
+}
+
+func mergeLogFields(fields ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, f := range fields {
+		for key, value := range f {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// redactBody returns the body as a string safe for logging. Values of sensitive keys and of
+// environment variables marked as secret are replaced, and non JSON bodies are only summarized.
+func redactBody(body []byte) string {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes of non JSON data>", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if secret, ok := v["isSecret"].(bool); ok && secret {
+			if _, ok := v["value"]; ok {
+				v["value"] = redactedValue
+			}
+		}
+
+		for key, item := range v {
+			if _, ok := item.(string); ok && isSensitiveBodyKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveBodyKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveBodyKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestRedactBody(t *testing.T) {
+	testCases := map[string]struct {
+		body     string
+		expected string
+	}{
+		"empty": {
+			body:     "",
+			expected: "",
+		},
+		"non json": {
+			body:     "plain text",
+			expected: "<10 bytes of non JSON data>",
+		},
+		"secret env": {
+			body:     `{"env":[{"key":"A","value":"public","isSecret":false},{"key":"B","value":"hidden","isSecret":true}]}`,
+			expected: `{"env":[{"isSecret":false,"key":"A","value":"public"},{"isSecret":true,"key":"B","value":"***"}]}`,
+		},
+		"sensitive keys": {
+			body:     `{"apiToken":"abc","nested":{"Password":"pwd","name":"n"}}`,
+			expected: `{"apiToken":"***","nested":{"Password":"***","name":"n"}}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if actual := redactBody([]byte(tc.body)); actual != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSendApiRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "server-"+r.Header.Get(requestIDHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"secret-token","name":"instance"}`))
+	}))
+	defer server.Close()
+
+	api, err := NewSpheronApi("secret-token", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	t.Setenv("TF_LOG_PROVIDER_SPHERON_CLIENT", "TRACE")
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	if _, err := api.sendApiRequest(ctx, HttpMethodPost, "/v1/test", map[string]interface{}{"value": "v", "isSecret": true}, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	logs := output.String()
+	if strings.Contains(logs, "secret-token") || !strings.Contains(logs, `\"value\":\"***\"`) {
+		t.Errorf("expected secrets to be redacted, got %s", logs)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode logs: %s", err)
+	}
+
+	var response map[string]interface{}
+	for _, entry := range entries {
+		if entry["@module"] != "provider."+logSubsystem {
+			t.Errorf("unexpected log module %v", entry["@module"])
+		}
+		if entry["@message"] == "Received Spheron API response" {
+			response = entry
+		}
+	}
+
+	if response == nil {
+		t.Fatalf("expected response log entry, got %v", entries)
+	}
+	if response["method"] != HttpMethodPost || response["path"] != "/v1/test" || response["status"] != float64(http.StatusOK) {
+		t.Errorf("unexpected response log entry %v", response)
+	}
+	if _, ok := response["duration_ms"]; !ok {
+		t.Errorf("expected duration_ms in %v", response)
+	}
+	if id, _ := response["request_id"].(string); !strings.HasPrefix(id, "server-") {
+		t.Errorf("expected request id from response header, got %v", response["request_id"])
+	}
+}
+
+func TestSendApiRequestLoggingSensitiveValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"env":["PASSWORD=s3cret"],"message":"deployed with s3cret"}`))
+	}))
+	defer server.Close()
+
+	api, err := NewSpheronApi("secret-token", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	t.Setenv("TF_LOG_PROVIDER_SPHERON_CLIENT", "TRACE")
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = WithSensitiveValues(ctx, "s3cret", "")
+
+	if _, err := api.sendApiRequest(ctx, HttpMethodPost, "/v1/test", map[string]interface{}{"value": "PASSWORD=s3cret"}, nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	logs := output.String()
+	if strings.Contains(logs, "s3cret") {
+		t.Errorf("expected sensitive values to be masked, got %s", logs)
+	}
+	if !strings.Contains(logs, "PASSWORD=***") {
+		t.Errorf("expected masked value in logs, got %s", logs)
+	}
+}
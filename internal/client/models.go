@@ -7,6 +7,8 @@ import (
 type TokenScope struct {
 	User          User                `json:"user"`
 	Organizations []TokenOrganization `json:"organizations"`
+	Permissions   []string            `json:"permissions,omitempty"`
+	ExpiresAt     *time.Time          `json:"expiresAt,omitempty"`
 }
 
 type User struct {
@@ -62,16 +64,49 @@ type InstanceConfiguration struct {
 }
 
 type CustomInstanceSpecs struct {
-	CPU               string             `json:"cpu,omitempty"`
-	Memory            string             `json:"memory,omitempty"`
-	PersistentStorage *PersistentStorage `json:"persistentStorage,omitempty"`
-	Storage           string             `json:"storage"`
+	CPU               string              `json:"cpu,omitempty"`
+	Memory            string              `json:"memory,omitempty"`
+	PersistentStorage *PersistentStorage  `json:"persistentStorage,omitempty"`
+	Volumes           []PersistentStorage `json:"volumes,omitempty"`
+	Storage           string              `json:"storage"`
+	Gpu               *GpuSpecs           `json:"gpu,omitempty"`
+}
+
+// SetVolumes sends a single unnamed volume as persistentStorage, which is how instances with one
+// persistent storage are deployed, and named volumes as volumes.
+func (s *CustomInstanceSpecs) SetVolumes(volumes []PersistentStorage) {
+	s.PersistentStorage = nil
+	s.Volumes = nil
+
+	if len(volumes) == 1 && volumes[0].Name == "" {
+		s.PersistentStorage = &volumes[0]
+		return
+	}
+	s.Volumes = volumes
+}
+
+// GetVolumes returns the persistentStorage and the volumes of the specs as one list.
+func (s CustomInstanceSpecs) GetVolumes() []PersistentStorage {
+	return joinVolumes(s.PersistentStorage, s.Volumes)
+}
+
+type GpuSpecs struct {
+	Count  int    `json:"count"`
+	Vendor string `json:"vendor,omitempty"`
+	Model  string `json:"model"`
+	Vram   string `json:"vram,omitempty"`
 }
 
 type PersistentStorage struct {
+	Name       string `json:"name,omitempty"`
 	Class      string `json:"class,omitempty"`
 	MountPoint string `json:"mountPoint,omitempty"`
 	Size       string `json:"size,omitempty"`
+	ReadOnly   bool   `json:"readOnly,omitempty"`
+}
+
+type ResizeVolumeRequest struct {
+	Size string `json:"size"`
 }
 
 type UpdateInstanceRequest struct {
@@ -83,6 +118,15 @@ type UpdateInstanceRequest struct {
 	OrganizationID string   `json:"organizationId"`
 }
 
+type StartInstanceRequest struct {
+	UniqueTopicID  string `json:"uniqueTopicId"`
+	OrganizationID string `json:"organizationId"`
+}
+
+type EscrowTopUpRequest struct {
+	Amount int `json:"amount"`
+}
+
 type HealthCheckUpdateReq struct {
 	HealthCheckURL  string `json:"healthCheckUrl"`
 	HealthCheckPort int    `json:"healthCheckPort"`
@@ -138,12 +182,33 @@ type Instance struct {
 	UpdatedAt              time.Time        `json:"updatedAt"`
 }
 
+type ActiveInstance struct {
+	ID           string  `json:"_id"`
+	Name         string  `json:"name"`
+	PricePerHour float64 `json:"pricePerHour"`
+}
+
 type MachineImageType struct {
-	MachineType       string             `json:"machineType"`
-	Storage           string             `json:"storage"`
-	Cpu               float32            `json:"cpu"`
-	Memory            string             `json:"memory"`
-	PersistentStorage *PersistentStorage `json:"persistentStorage,omitempty"`
+	MachineType       string              `json:"machineType"`
+	Storage           string              `json:"storage"`
+	Cpu               float32             `json:"cpu"`
+	Memory            string              `json:"memory"`
+	PersistentStorage *PersistentStorage  `json:"persistentStorage,omitempty"`
+	Volumes           []PersistentStorage `json:"volumes,omitempty"`
+	Gpu               *GpuSpecs           `json:"gpu,omitempty"`
+}
+
+// GetVolumes returns the persistentStorage and the volumes of the machine image as one list.
+func (m MachineImageType) GetVolumes() []PersistentStorage {
+	return joinVolumes(m.PersistentStorage, m.Volumes)
+}
+
+func joinVolumes(persistentStorage *PersistentStorage, volumes []PersistentStorage) []PersistentStorage {
+	if persistentStorage == nil || persistentStorage.Class == "" {
+		return volumes
+	}
+
+	return append([]PersistentStorage{*persistentStorage}, volumes...)
 }
 
 type HealthCheck struct {
@@ -164,12 +229,25 @@ type DomainResponse struct {
 }
 
 type Domain struct {
-	ID         string         `json:"_id"`
-	Name       string         `json:"name"`
-	Verified   bool           `json:"verified"`
-	Link       string         `json:"link"`
-	Type       DomainTypeEnum `json:"type"`
-	InstanceID string         `json:"instanceId"`
+	ID          string             `json:"_id"`
+	Name        string             `json:"name"`
+	Verified    bool               `json:"verified"`
+	Link        string             `json:"link"`
+	Type        DomainTypeEnum     `json:"type"`
+	InstanceID  string             `json:"instanceId"`
+	DNSRecords  []DomainDNSRecord  `json:"dnsRecords,omitempty"`
+	Certificate *DomainCertificate `json:"certificate,omitempty"`
+}
+
+type DomainCertificate struct {
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+type DomainDNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 type DomainTypeEnum string
@@ -177,6 +255,10 @@ type DomainTypeEnum string
 const (
 	DomainTypeDomain    DomainTypeEnum = "domain"
 	DomainTypeSubdomain DomainTypeEnum = "subdomain"
+	DomainTypeWildcard  DomainTypeEnum = "wildcard"
+	DomainTypeApex      DomainTypeEnum = "apex"
+	DomainTypeEns       DomainTypeEnum = "ens"
+	DomainTypeHns       DomainTypeEnum = "hns"
 )
 
 type InstanceOrder struct {
@@ -186,6 +268,7 @@ type InstanceOrder struct {
 	URLPreview                   string                        `json:"urlPrewiew"`
 	ProtocolData                 *ProtocolData                 `json:"protocolData,omitempty"`
 	ClusterInstanceConfiguration *ClusterInstanceConfiguration `json:"clusterInstanceConfiguration,omitempty"`
+	CreatedAt                    time.Time                     `json:"createdAt"`
 }
 
 type ProtocolData struct {
@@ -211,7 +294,10 @@ type MarketplaceApp struct {
 }
 
 type MarketplaceAppServiceData struct {
-	Variables []MarketplaceAppVariable `json:"variables"`
+	DockerImage    string                   `json:"dockerImage"`
+	DockerImageTag string                   `json:"dockerImageTag"`
+	Versions       []string                 `json:"versions,omitempty"`
+	Variables      []MarketplaceAppVariable `json:"variables"`
 }
 
 type MarketplaceAppVariable struct {
@@ -230,6 +316,7 @@ type CreateInstanceFromMarketplaceRequest struct {
 	Region               string                          `json:"region"`
 	CustomInstanceSpecs  CustomInstanceSpecs             `json:"customInstanceSpecs"`
 	InstanceCount        int                             `json:"instanceCount"`
+	Tag                  string                          `json:"tag,omitempty"`
 }
 
 type MarketplaceDeploymentVariable struct {
@@ -238,8 +325,58 @@ type MarketplaceDeploymentVariable struct {
 }
 
 type ComputeMachine struct {
-	ID   string `json:"_id"`
-	Name string `json:"name"`
+	ID     string  `json:"_id"`
+	Name   string  `json:"name"`
+	Cpu    float32 `json:"cpu"`
+	Memory string  `json:"memory"`
+}
+
+type Region struct {
+	Name     string         `json:"name"`
+	Capacity RegionCapacity `json:"capacity"`
+	Pricing  RegionPricing  `json:"pricing"`
+	Gpus     []RegionGpu    `json:"gpus"`
+}
+
+type RegionCapacity struct {
+	Cpu     float64 `json:"cpu"`
+	Memory  float64 `json:"memory"`
+	Storage float64 `json:"storage"`
+}
+
+type RegionPricing struct {
+	CpuPerHour     float64 `json:"cpuPerHour"`
+	MemoryPerHour  float64 `json:"memoryPerHour"`
+	StoragePerHour float64 `json:"storagePerHour"`
+}
+
+type RegionGpu struct {
+	Vendor       string  `json:"vendor"`
+	Model        string  `json:"model"`
+	Vram         string  `json:"vram"`
+	Available    int     `json:"available"`
+	PricePerHour float64 `json:"pricePerHour"`
+}
+
+// InstanceLimits are the CPU in cores and memory in GB that a single instance replica can request.
+type InstanceLimits struct {
+	MinCpu    float64 `json:"minCpu"`
+	MaxCpu    float64 `json:"maxCpu"`
+	MinMemory float64 `json:"minMemory"`
+	MaxMemory float64 `json:"maxMemory"`
+}
+
+type InstancePriceRequest struct {
+	Region                string              `json:"region"`
+	AkashMachineImageName string              `json:"akashMachineImageName,omitempty"`
+	CustomInstanceSpecs   CustomInstanceSpecs `json:"customInstanceSpecs"`
+	InstanceCount         int                 `json:"instanceCount"`
+	Scalable              bool                `json:"scalable"`
+}
+
+type InstancePrice struct {
+	PricePerHour  float64 `json:"pricePerHour"`
+	PricePerMonth float64 `json:"pricePerMonth"`
 }
 
 type Cluster struct {
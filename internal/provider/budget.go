@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// spendBudget enforces the provider spend limits on the planned instances together with the instances
+// already running in the organization.
+type spendBudget struct {
+	maxHourlySpend types.Float64
+	maxInstances   types.Int64
+	api            *client.SpheronApi
+
+	mu           sync.Mutex
+	loaded       bool
+	existing     map[string]float64
+	reservations map[string]reservation
+	created      int
+}
+
+// reservation is the planned cost of a single instance. Destroyed instances are kept so they are left out of the
+// existing instances.
+type reservation struct {
+	costPerHour float64
+	destroyed   bool
+}
+
+func newSpendBudget(maxHourlySpend types.Float64, maxInstances types.Int64) *spendBudget {
+	return &spendBudget{
+		maxHourlySpend: maxHourlySpend,
+		maxInstances:   maxInstances,
+		existing:       map[string]float64{},
+		reservations:   map[string]reservation{},
+	}
+}
+
+// reserve records the planned cost of an instance and checks the limits. Existing instances are keyed by id, so
+// planning them replaces their current cost. Every new instance gets its own reservation, since instances that
+// are not created yet can share a name.
+func (b *spendBudget) reserve(ctx context.Context, id types.String, name string, costPerHour types.Float64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if b == nil || (b.maxHourlySpend.IsNull() && b.maxInstances.IsNull()) {
+		return diags
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	diags.Append(b.loadExisting(ctx)...)
+
+	cost := 0.0
+	if !costPerHour.IsNull() && !costPerHour.IsUnknown() {
+		cost = costPerHour.ValueFloat64()
+	}
+	b.reservations[b.reservationKey(id)] = reservation{costPerHour: cost}
+
+	instances, hourlySpend := b.total()
+
+	if !b.maxInstances.IsNull() && instances > b.maxInstances.ValueInt64() {
+		diags.AddError(
+			"Instance limit exceeded.",
+			fmt.Sprintf("Instance %s would bring the number of instances to %d, which exceeds max_instances of %d.", name, instances, b.maxInstances.ValueInt64()),
+		)
+	}
+
+	if b.maxHourlySpend.IsNull() {
+		return diags
+	}
+
+	if costPerHour.IsNull() || costPerHour.IsUnknown() {
+		diags.AddWarning(
+			"Unable to enforce hourly spend limit.",
+			fmt.Sprintf("Estimated cost of instance %s is not known, so it is not included in max_hourly_spend.", name),
+		)
+		return diags
+	}
+
+	if hourlySpend > b.maxHourlySpend.ValueFloat64() {
+		diags.AddError(
+			"Hourly spend limit exceeded.",
+			fmt.Sprintf("Instance %s would bring the estimated hourly spend to $%.4f, which exceeds max_hourly_spend of $%.4f.", name, hourlySpend, b.maxHourlySpend.ValueFloat64()),
+		)
+	}
+
+	return diags
+}
+
+// release leaves an instance that is planned to be destroyed out of the limits.
+func (b *spendBudget) release(id types.String) {
+	if b == nil || id.IsNull() || id.IsUnknown() || id.ValueString() == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reservations[id.ValueString()] = reservation{destroyed: true}
+}
+
+func (b *spendBudget) loadExisting(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if b.loaded || b.api == nil {
+		return diags
+	}
+	b.loaded = true
+
+	instances, err := b.api.GetActiveClusterInstances(ctx)
+	if err != nil {
+		diags.AddWarning(
+			"Unable to read existing instances.",
+			fmt.Sprintf("Spend limits are enforced only on the planned instances: %s", err.Error()),
+		)
+		return diags
+	}
+
+	for _, instance := range instances {
+		b.existing[instance.ID] = instance.PricePerHour
+	}
+
+	return diags
+}
+
+func (b *spendBudget) total() (int64, float64) {
+	var instances int64
+	var hourlySpend float64
+
+	for id, costPerHour := range b.existing {
+		if _, ok := b.reservations[id]; !ok {
+			instances++
+			hourlySpend += costPerHour
+		}
+	}
+
+	for _, reservation := range b.reservations {
+		if !reservation.destroyed {
+			instances++
+			hourlySpend += reservation.costPerHour
+		}
+	}
+
+	return instances, hourlySpend
+}
+
+func (b *spendBudget) reservationKey(id types.String) string {
+	if id.IsNull() || id.IsUnknown() || id.ValueString() == "" {
+		b.created++
+		return fmt.Sprintf("new/%d", b.created)
+	}
+
+	return id.ValueString()
+}
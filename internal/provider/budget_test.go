@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestSpendBudgetReserve(t *testing.T) {
+	ctx := context.Background()
+	budget := newSpendBudget(types.Float64Value(1), types.Int64Value(2))
+
+	if diags := budget.reserve(ctx, types.StringNull(), "first", types.Float64Value(0.6)); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	diags := budget.reserve(ctx, types.StringNull(), "second", types.Float64Value(0.6))
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Hourly spend limit exceeded." {
+		t.Fatalf("expected hourly spend error, got %v", diags)
+	}
+
+	diags = budget.reserve(ctx, types.StringNull(), "third", types.Float64Unknown())
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Instance limit exceeded." {
+		t.Fatalf("expected instance limit error, got %v", diags)
+	}
+	if len(diags.Warnings()) != 1 {
+		t.Errorf("expected warning for unknown cost, got %v", diags)
+	}
+
+	var unlimited *spendBudget
+	if diags := unlimited.reserve(ctx, types.StringNull(), "any", types.Float64Value(100)); diags.HasError() {
+		t.Errorf("expected nil budget to allow everything, got %v", diags)
+	}
+
+	noLimits := newSpendBudget(types.Float64Null(), types.Int64Null())
+	for i := 0; i < 10; i++ {
+		if diags := noLimits.reserve(ctx, types.StringNull(), fmt.Sprintf("instance-%d", i), types.Float64Value(100)); len(diags) != 0 {
+			t.Fatalf("expected no diagnostics without limits, got %v", diags)
+		}
+	}
+}
+
+func TestSpendBudgetReserveSameName(t *testing.T) {
+	ctx := context.Background()
+	budget := newSpendBudget(types.Float64Value(1), types.Int64Value(1))
+
+	if diags := budget.reserve(ctx, types.StringNull(), "app", types.Float64Value(0.6)); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	diags := budget.reserve(ctx, types.StringNull(), "app", types.Float64Value(0.6))
+	if diags.ErrorsCount() != 2 {
+		t.Fatalf("expected new instances with the same name to count toward both limits, got %v", diags)
+	}
+
+	if instances, hourlySpend := budget.total(); instances != 2 || hourlySpend != 1.2 {
+		t.Errorf("expected 2 instances costing $1.2 per hour, got %d costing $%g", instances, hourlySpend)
+	}
+}
+
+func TestSpendBudgetExistingInstances(t *testing.T) {
+	ctx := context.Background()
+	budget := newSpendBudget(types.Float64Value(1), types.Int64Value(2))
+	budget.loaded = true
+	budget.existing = map[string]float64{"instance-1": 0.5, "instance-2": 0.3}
+
+	diags := budget.reserve(ctx, types.StringNull(), "new", types.Float64Value(0.1))
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Instance limit exceeded." {
+		t.Fatalf("expected instance limit error with existing instances, got %v", diags)
+	}
+
+	budget.release(types.StringValue("instance-2"))
+	if diags := budget.reserve(ctx, types.StringValue("instance-1"), "updated", types.Float64Value(0.8)); diags.HasError() {
+		t.Fatalf("expected updated instance to replace its existing cost, got %v", diags)
+	}
+
+	diags = budget.reserve(ctx, types.StringValue("instance-1"), "updated", types.Float64Value(1))
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Hourly spend limit exceeded." {
+		t.Fatalf("expected hourly spend error, got %v", diags)
+	}
+}
+
+func TestAccProvider_budget(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "spheron" {
+  max_hourly_spend = 0.03
+}
+` + testAccBudgetInstancesConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Hourly spend limit exceeded`),
+			},
+			{
+				Config: `
+provider "spheron" {
+  max_instances = 1
+}
+` + testAccBudgetInstancesConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Instance limit exceeded`),
+			},
+			{
+				Config: `
+provider "spheron" {
+  max_hourly_spend = 0.05
+  max_instances    = 2
+}
+` + testAccBudgetInstancesConfig,
+			},
+		},
+	})
+}
+
+func TestAccProvider_budgetExistingInstances(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.AddInstance("tf_test_budget_console")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "spheron" {
+  max_instances = 2
+}
+` + testAccBudgetInstancesConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`bring the number of instances to 3`),
+			},
+			{
+				Config: `
+provider "spheron" {
+  max_hourly_spend = 0.05
+}
+` + testAccBudgetInstancesConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Hourly spend limit exceeded`),
+			},
+			{
+				Config: `
+provider "spheron" {
+  max_hourly_spend = 0.1
+  max_instances    = 3
+}
+` + testAccBudgetInstancesConfig,
+			},
+			{
+				Config: `
+provider "spheron" {
+  max_hourly_spend = 0.1
+  max_instances    = 3
+}
+` + testAccBudgetInstancesConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccProvider_budgetSameName(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "spheron" {
+  max_instances = 1
+}
+
+resource "spheron_instance" "test" {
+  count = 2
+
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_budget_count"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`bring the number of instances to 2`),
+			},
+		},
+	})
+}
+
+const testAccBudgetInstancesConfig = `
+resource "spheron_instance" "first" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_budget_first"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+
+resource "spheron_instance" "second" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_budget_second"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`
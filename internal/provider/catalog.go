@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func validateCatalogName(kind string, value string, options []string) error {
+	if containsString(options, value) {
+		return nil
+	}
+
+	message := fmt.Sprintf("%s %q is not available.", kind, value)
+	if suggestion := suggestName(value, options); suggestion != "" {
+		message += fmt.Sprintf(" Did you mean %q?", suggestion)
+	}
+
+	return fmt.Errorf("%s Available values are: %s", message, strings.Join(options, ", "))
+}
+
+func suggestName(value string, options []string) string {
+	suggestion := ""
+	bestDistance := utf8.RuneCountInString(value)/3 + 2
+
+	for _, option := range options {
+		distance := levenshteinDistance(strings.ToLower(value), strings.ToLower(option))
+		if distance < bestDistance {
+			suggestion = option
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+// levenshteinDistance counts the single character edits between two names, comparing runes so that non-ASCII names aren't penalized per byte.
+func levenshteinDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func validateRegion(regions []client.Region, region string) error {
+	options := []string{"any"}
+	for _, r := range regions {
+		options = append(options, r.Name)
+	}
+
+	return validateCatalogName("Region", region, options)
+}
+
+type instanceSpec struct {
+	Cpu     float64
+	Memory  float64
+	Storage float64
+}
+
+func (s instanceSpec) String() string {
+	return fmt.Sprintf("%g CPU, %gGi memory and %gGi storage", s.Cpu, s.Memory, s.Storage)
+}
+
+func checkRegionCapacity(regions []client.Region, region string, spec instanceSpec) error {
+	if region == "any" {
+		for _, r := range regions {
+			if regionHasCapacity(r, spec) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("No region has enough capacity for %s.", spec)
+	}
+
+	for _, r := range regions {
+		if r.Name != region {
+			continue
+		}
+
+		if regionHasCapacity(r, spec) {
+			return nil
+		}
+
+		available := instanceSpec{Cpu: r.Capacity.Cpu, Memory: r.Capacity.Memory, Storage: r.Capacity.Storage}
+		return fmt.Errorf("Region %q has %s available, but %s was requested.", region, available, spec)
+	}
+
+	return nil
+}
+
+func regionHasCapacity(region client.Region, spec instanceSpec) bool {
+	return region.Capacity.Cpu >= spec.Cpu && region.Capacity.Memory >= spec.Memory && region.Capacity.Storage >= spec.Storage
+}
+
+func mapClientRegionsToRegions(regions []client.Region) []RegionModel {
+	mapped := make([]RegionModel, 0, len(regions))
+	for _, region := range regions {
+		mapped = append(mapped, RegionModel{
+			Name:                types.StringValue(region.Name),
+			AvailableCpu:        types.Float64Value(region.Capacity.Cpu),
+			AvailableMemory:     types.Float64Value(region.Capacity.Memory),
+			AvailableStorage:    types.Float64Value(region.Capacity.Storage),
+			CpuPricePerHour:     types.Float64Value(region.Pricing.CpuPerHour),
+			MemoryPricePerHour:  types.Float64Value(region.Pricing.MemoryPerHour),
+			StoragePricePerHour: types.Float64Value(region.Pricing.StoragePerHour),
+		})
+	}
+
+	return mapped
+}
+
+func validateMachineImage(machines []client.ComputeMachine, name string) error {
+	options := make([]string, 0, len(machines))
+	for _, machine := range machines {
+		options = append(options, machine.Name)
+	}
+
+	return validateCatalogName("Machine image", name, options)
+}
+
+func validateInstanceCatalog(ctx context.Context, api *client.SpheronApi, region types.String, stateRegion types.String, machineImage types.String, stateMachineImage types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if isPlannedChange(region, stateRegion) && region.ValueString() != "any" {
+		regions, err := api.GetRegions(ctx)
+		if err != nil {
+			diags.AddAttributeWarning(path.Root("region"), "Unable to validate region.", err.Error())
+		} else if err := validateRegion(regions, region.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("region"), "Invalid region.", err.Error())
+		}
+	}
+
+	if isPlannedChange(machineImage, stateMachineImage) && machineImage.ValueString() != "" && machineImage.ValueString() != "Custom Plan" {
+		machines, err := api.GetComputeMachines(ctx)
+		if err != nil {
+			diags.AddAttributeWarning(path.Root("machine_image"), "Unable to validate machine image.", err.Error())
+		} else if err := validateMachineImage(machines, machineImage.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("machine_image"), "Invalid machine image.", err.Error())
+		}
+	}
+
+	return diags
+}
+
+func isPlannedChange(planValue types.String, stateValue types.String) bool {
+	return !planValue.IsUnknown() && !planValue.IsNull() && !planValue.Equal(stateValue)
+}
+
+func validateMarketplaceAppName(apps []client.MarketplaceApp, name string) error {
+	options := make([]string, 0, len(apps))
+	for _, app := range apps {
+		options = append(options, app.Name)
+	}
+
+	return validateCatalogName("Marketplace app", name, options)
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+)
+
+func TestSuggestName(t *testing.T) {
+	options := []string{"us-east", "us-west", "eu-west", "Ventus Small", "Zürich", "東京都"}
+
+	testCases := map[string]string{
+		"us-eats":     "us-east",
+		"US-WEST":     "us-west",
+		"ventus smal": "Ventus Small",
+		"asia-south":  "",
+		"":            "",
+		"Zurih":       "Zürich",
+		"東京":          "東京都",
+	}
+
+	for input, expected := range testCases {
+		if got := suggestName(input, options); got != expected {
+			t.Errorf("suggestName(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"us-east", "us-eats", 2},
+		{"zurich", "zürich", 1},
+		{"東京", "東京都", 1},
+		{"", "any", 3},
+	}
+
+	for _, tc := range testCases {
+		if got := levenshteinDistance(tc.a, tc.b); got != tc.expected {
+			t.Errorf("levenshteinDistance(%q, %q): expected %d, got %d", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func TestValidateCatalogName(t *testing.T) {
+	options := []string{"any", "us-east", "us-west"}
+
+	if err := validateCatalogName("Region", "us-east", options); err != nil {
+		t.Errorf("expected no error for available value, got %v", err)
+	}
+
+	err := validateCatalogName("Region", "us-est", options)
+	if err == nil {
+		t.Fatal("expected error for unavailable value")
+	}
+	expected := `Region "us-est" is not available. Did you mean "us-east"? Available values are: any, us-east, us-west`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	err = validateCatalogName("Region", "asia-south", options)
+	if err == nil || strings.Contains(err.Error(), "Did you mean") {
+		t.Errorf("expected error without suggestion, got %v", err)
+	}
+}
+
+func TestCheckRegionCapacity(t *testing.T) {
+	regions := []client.Region{
+		{Name: "us-east", Capacity: client.RegionCapacity{Cpu: 8, Memory: 16, Storage: 200}},
+		{Name: "eu-west", Capacity: client.RegionCapacity{Cpu: 2, Memory: 4, Storage: 100}},
+	}
+
+	testCases := []struct {
+		region  string
+		spec    instanceSpec
+		wantErr string
+	}{
+		{region: "eu-west", spec: instanceSpec{Cpu: 2, Memory: 4, Storage: 100}},
+		{region: "eu-west", spec: instanceSpec{Cpu: 4, Memory: 4, Storage: 10}, wantErr: `Region "eu-west" has 2 CPU, 4Gi memory and 100Gi storage available, but 4 CPU, 4Gi memory and 10Gi storage was requested.`},
+		{region: "any", spec: instanceSpec{Cpu: 8, Memory: 16, Storage: 200}},
+		{region: "any", spec: instanceSpec{Cpu: 16, Memory: 1, Storage: 1}, wantErr: "No region has enough capacity for 16 CPU, 1Gi memory and 1Gi storage."},
+		{region: "ap-south", spec: instanceSpec{Cpu: 64}},
+	}
+
+	for _, tc := range testCases {
+		err := checkRegionCapacity(regions, tc.region, tc.spec)
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("checkRegionCapacity(%q, %s): unexpected error %v", tc.region, tc.spec, err)
+		}
+		if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("checkRegionCapacity(%q, %s): expected %q, got %v", tc.region, tc.spec, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	composeFormatCompose = "compose"
+	composeFormatSDL     = "sdl"
+)
+
+// composeService holds the values of a compose or SDL service that map to spheron_instance attributes.
+type composeService struct {
+	Image    string
+	Tag      string
+	Ports    []composePort
+	Env      []composeEnv
+	Commands []string
+	Args     []string
+	Cpu      string
+	Memory   string
+	Storage  int64
+	Replicas int64
+	// InternalPorts are the SDL ports that are only exposed to the other services, which are left out of Ports.
+	InternalPorts []int64
+}
+
+type composePort struct {
+	ContainerPort int64
+	ExposedPort   int64
+}
+
+type composeEnv struct {
+	Key   string
+	Value string
+}
+
+type composeFile struct {
+	Version    string                           `yaml:"version"`
+	Services   map[string]composeFileService    `yaml:"services"`
+	Profiles   *sdlProfiles                     `yaml:"profiles"`
+	Deployment map[string]map[string]sdlPlacing `yaml:"deployment"`
+}
+
+type composeFileService struct {
+	Image       string      `yaml:"image"`
+	Ports       []yaml.Node `yaml:"ports"`
+	Environment yaml.Node   `yaml:"environment"`
+	Entrypoint  yaml.Node   `yaml:"entrypoint"`
+	Command     yaml.Node   `yaml:"command"`
+	Deploy      struct {
+		Replicas  *int64 `yaml:"replicas"`
+		Resources struct {
+			Limits struct {
+				Cpus   string `yaml:"cpus"`
+				Memory string `yaml:"memory"`
+			} `yaml:"limits"`
+		} `yaml:"resources"`
+	} `yaml:"deploy"`
+
+	// Akash SDL service fields.
+	Env    []string    `yaml:"env"`
+	Args   yaml.Node   `yaml:"args"`
+	Expose []sdlExpose `yaml:"expose"`
+}
+
+type sdlExpose struct {
+	Port int64 `yaml:"port"`
+	As   int64 `yaml:"as"`
+	To   []struct {
+		Global bool `yaml:"global"`
+	} `yaml:"to"`
+}
+
+type sdlProfiles struct {
+	Compute map[string]struct {
+		Resources struct {
+			Cpu struct {
+				Units string `yaml:"units"`
+			} `yaml:"cpu"`
+			Memory struct {
+				Size string `yaml:"size"`
+			} `yaml:"memory"`
+			Storage yaml.Node `yaml:"storage"`
+		} `yaml:"resources"`
+	} `yaml:"compute"`
+}
+
+type sdlPlacing struct {
+	Profile string `yaml:"profile"`
+	Count   int64  `yaml:"count"`
+}
+
+// parseComposeFile parses a docker-compose file or an Akash SDL and returns its services by name. Files
+// with compute profiles or deployments are read as SDL.
+func parseComposeFile(content string) (map[string]composeService, string, error) {
+	var file composeFile
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		return nil, "", fmt.Errorf("Unable to parse YAML: %s", err)
+	}
+
+	if len(file.Services) == 0 {
+		return nil, "", errors.New("File doesn't define any services.")
+	}
+
+	format := composeFormatCompose
+	if file.Profiles != nil || file.Deployment != nil {
+		format = composeFormatSDL
+	}
+
+	services := make(map[string]composeService, len(file.Services))
+	for name, fileService := range file.Services {
+		var service composeService
+		var err error
+
+		if format == composeFormatSDL {
+			service, err = parseSDLService(name, fileService, file)
+		} else {
+			service, err = parseComposeService(fileService)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("Service %s: %s", name, err)
+		}
+
+		services[name] = service
+	}
+
+	return services, format, nil
+}
+
+func parseComposeService(fileService composeFileService) (composeService, error) {
+	var service composeService
+	var err error
+
+	if service.Image, service.Tag, err = splitImageTag(fileService.Image); err != nil {
+		return service, err
+	}
+
+	for _, node := range fileService.Ports {
+		port, err := parseComposePort(node)
+		if err != nil {
+			return service, err
+		}
+		service.Ports = append(service.Ports, port)
+	}
+
+	if service.Env, err = parseComposeEnvironment(fileService.Environment); err != nil {
+		return service, err
+	}
+
+	if service.Commands, err = parseComposeCommand(fileService.Entrypoint); err != nil {
+		return service, fmt.Errorf("Invalid entrypoint: %s", err)
+	}
+	if service.Args, err = parseComposeCommand(fileService.Command); err != nil {
+		return service, fmt.Errorf("Invalid command: %s", err)
+	}
+
+	limits := fileService.Deploy.Resources.Limits
+	if limits.Cpus != "" {
+		if service.Cpu, err = normalizeCpu(limits.Cpus); err != nil {
+			return service, err
+		}
+	}
+	if limits.Memory != "" {
+		if service.Memory, err = normalizeMemory(limits.Memory); err != nil {
+			return service, err
+		}
+	}
+
+	service.Replicas = 1
+	if fileService.Deploy.Replicas != nil {
+		service.Replicas = *fileService.Deploy.Replicas
+	}
+
+	return service, nil
+}
+
+func parseSDLService(name string, fileService composeFileService, file composeFile) (composeService, error) {
+	var service composeService
+	var err error
+
+	if service.Image, service.Tag, err = splitImageTag(fileService.Image); err != nil {
+		return service, err
+	}
+
+	// Ports that are not exposed globally are only reachable by the other services of the SDL, while every
+	// port of spheron_instance is public, so they are left out.
+	for _, expose := range fileService.Expose {
+		global := false
+		for _, to := range expose.To {
+			global = global || to.Global
+		}
+		if !global {
+			service.InternalPorts = append(service.InternalPorts, expose.Port)
+			continue
+		}
+
+		port := composePort{ContainerPort: expose.Port}
+		if expose.As != 0 {
+			port.ExposedPort = expose.As
+		}
+		service.Ports = append(service.Ports, port)
+	}
+
+	for _, value := range fileService.Env {
+		key, value := splitClientEnv(value)
+		service.Env = append(service.Env, composeEnv{Key: key, Value: value})
+	}
+
+	if service.Commands, err = parseComposeCommand(fileService.Command); err != nil {
+		return service, fmt.Errorf("Invalid command: %s", err)
+	}
+	if service.Args, err = parseComposeCommand(fileService.Args); err != nil {
+		return service, fmt.Errorf("Invalid args: %s", err)
+	}
+
+	// The deployment placement of the service names its compute profile, which defaults to the service name.
+	profileName := name
+	service.Replicas = 1
+	for _, placing := range file.Deployment[name] {
+		if placing.Profile != "" {
+			profileName = placing.Profile
+		}
+		if placing.Count != 0 {
+			service.Replicas = placing.Count
+		}
+	}
+
+	if file.Profiles == nil {
+		return service, nil
+	}
+
+	profile, ok := file.Profiles.Compute[profileName]
+	if !ok {
+		return service, nil
+	}
+
+	if units := profile.Resources.Cpu.Units; units != "" {
+		if service.Cpu, err = normalizeCpu(units); err != nil {
+			return service, err
+		}
+	}
+	if size := profile.Resources.Memory.Size; size != "" {
+		if service.Memory, err = normalizeMemory(size); err != nil {
+			return service, err
+		}
+	}
+	if service.Storage, err = parseSDLStorage(profile.Resources.Storage); err != nil {
+		return service, err
+	}
+
+	return service, nil
+}
+
+// splitImageTag splits an image reference into the image and its tag, defaulting the tag to latest.
+func splitImageTag(reference string) (string, string, error) {
+	if reference == "" {
+		return "", "", errors.New("Service has no image.")
+	}
+
+	if strings.Contains(reference, "@") {
+		return "", "", fmt.Errorf("Image %s is pinned by digest, which is not supported. Use a tag instead.", reference)
+	}
+
+	separator := strings.LastIndex(reference, ":")
+	if separator == -1 || separator < strings.LastIndex(reference, "/") {
+		return reference, "latest", nil
+	}
+
+	return reference[:separator], reference[separator+1:], nil
+}
+
+// parseComposePort reads the short "[host:]published:target[/protocol]" syntax and the long syntax of a port.
+func parseComposePort(node yaml.Node) (composePort, error) {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Target    int64  `yaml:"target"`
+			Published string `yaml:"published"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return composePort{}, fmt.Errorf("Invalid port: %s", err)
+		}
+
+		port := composePort{ContainerPort: long.Target}
+		if long.Published != "" {
+			published, err := strconv.ParseInt(long.Published, 10, 64)
+			if err != nil {
+				return composePort{}, fmt.Errorf("Invalid published port %s.", long.Published)
+			}
+			port.ExposedPort = published
+		}
+		return port, nil
+	}
+
+	value := strings.SplitN(node.Value, "/", 2)[0]
+	parts := strings.Split(value, ":")
+
+	target, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return composePort{}, fmt.Errorf("Invalid port %s.", node.Value)
+	}
+
+	port := composePort{ContainerPort: target}
+	if len(parts) > 1 {
+		published, err := strconv.ParseInt(parts[len(parts)-2], 10, 64)
+		if err != nil {
+			return composePort{}, fmt.Errorf("Invalid port %s. Port ranges are not supported.", node.Value)
+		}
+		port.ExposedPort = published
+	}
+
+	return port, nil
+}
+
+// parseComposeEnvironment reads the list and the map syntax of a compose environment, sorted by key.
+func parseComposeEnvironment(node yaml.Node) ([]composeEnv, error) {
+	var envs []composeEnv
+
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.SequenceNode:
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return nil, fmt.Errorf("Invalid environment: %s", err)
+		}
+		for _, value := range values {
+			key, value := splitClientEnv(value)
+			envs = append(envs, composeEnv{Key: key, Value: value})
+		}
+	case yaml.MappingNode:
+		var values map[string]*string
+		if err := node.Decode(&values); err != nil {
+			return nil, fmt.Errorf("Invalid environment: %s", err)
+		}
+		for key, value := range values {
+			env := composeEnv{Key: key}
+			if value != nil {
+				env.Value = *value
+			}
+			envs = append(envs, env)
+		}
+	default:
+		return nil, errors.New("Environment must be a list or a map.")
+	}
+
+	sort.SliceStable(envs, func(i, j int) bool { return envs[i].Key < envs[j].Key })
+
+	return envs, nil
+}
+
+// parseComposeCommand reads a command given either as a list or as a string, which is split into words like
+// a POSIX shell does.
+func parseComposeCommand(node yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case 0:
+		return nil, nil
+	case yaml.ScalarNode:
+		return splitShellWords(node.Value)
+	case yaml.SequenceNode:
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	default:
+		return nil, errors.New("Command must be a list or a string.")
+	}
+}
+
+// splitShellWords splits a command into words with the quoting rules of a POSIX shell. Variables, globs and
+// other expansions are kept as they are.
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			inWord = true
+			if i+1 < len(command) {
+				i++
+				if command[i] != '\n' {
+					word.WriteByte(command[i])
+				}
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("Unterminated single quote in %s.", command)
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(command); i++ {
+				if command[i] == '"' {
+					closed = true
+					break
+				}
+				// Inside double quotes a backslash only escapes the characters that are special there.
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("$`\"\\\n", command[i+1]) != -1 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("Unterminated double quote in %s.", command)
+			}
+		default:
+			inWord = true
+			word.WriteByte(c)
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+func formatPorts(ports []int64) string {
+	values := make([]string, 0, len(ports))
+	for _, port := range ports {
+		values = append(values, strconv.FormatInt(port, 10))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func parseSDLStorage(node yaml.Node) (int64, error) {
+	var sizes []string
+
+	switch node.Kind {
+	case 0:
+		return 0, nil
+	case yaml.MappingNode:
+		var storage struct {
+			Size string `yaml:"size"`
+		}
+		if err := node.Decode(&storage); err != nil {
+			return 0, fmt.Errorf("Invalid storage: %s", err)
+		}
+		sizes = append(sizes, storage.Size)
+	case yaml.SequenceNode:
+		var storage []struct {
+			Size       string `yaml:"size"`
+			Attributes struct {
+				Persistent bool `yaml:"persistent"`
+			} `yaml:"attributes"`
+		}
+		if err := node.Decode(&storage); err != nil {
+			return 0, fmt.Errorf("Invalid storage: %s", err)
+		}
+		// Persistent volumes are not part of the instance storage.
+		for _, volume := range storage {
+			if !volume.Attributes.Persistent {
+				sizes = append(sizes, volume.Size)
+			}
+		}
+	default:
+		return 0, errors.New("Storage must be a map or a list.")
+	}
+
+	var total float64
+	for _, size := range sizes {
+		gb, err := parseMemoryGB(size)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid storage size: %s", err)
+		}
+		total += gb
+	}
+
+	return int64(math.Ceil(total)), nil
+}
+
+// normalizeCpu converts a CPU value, either in cores or in millicores like 500m, to cores.
+func normalizeCpu(value string) (string, error) {
+	cores, err := parseCpu(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatFloat(cores, 'f', -1, 64), nil
+}
+
+// normalizeMemory converts a memory size to GB, the unit used by spheron_instance.
+func normalizeMemory(value string) (string, error) {
+	gb, err := parseMemoryGB(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatFloat(gb, 'f', -1, 64), nil
+}
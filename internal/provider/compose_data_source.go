@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &ComposeDataSource{}
+
+func NewComposeDataSource() datasource.DataSource {
+	return &ComposeDataSource{}
+}
+
+// ComposeDataSource only parses its input, so it doesn't use the Spheron API.
+type ComposeDataSource struct{}
+
+type ComposeDataSourceModel struct {
+	ID       types.String                   `tfsdk:"id"`
+	Content  types.String                   `tfsdk:"content"`
+	Format   types.String                   `tfsdk:"format"`
+	Services map[string]ComposeServiceModel `tfsdk:"services"`
+}
+
+type ComposeServiceModel struct {
+	Image    types.String `tfsdk:"image"`
+	Tag      types.String `tfsdk:"tag"`
+	Ports    []Port       `tfsdk:"ports"`
+	Env      []Env        `tfsdk:"env"`
+	Commands []string     `tfsdk:"commands"`
+	Args     []string     `tfsdk:"args"`
+	Cpu      types.String `tfsdk:"cpu"`
+	Memory   types.String `tfsdk:"memory"`
+	Storage  types.Int64  `tfsdk:"storage"`
+	Replicas types.Int64  `tfsdk:"replicas"`
+}
+
+func (d *ComposeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_compose"
+}
+
+func (d *ComposeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Compose data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier.",
+				Computed:            true,
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Content of a docker-compose file or an Akash SDL, usually read with the file function.",
+				Required:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Detected format of the content. Either compose or sdl.",
+				Computed:            true,
+			},
+			"services": schema.MapNestedAttribute{
+				MarkdownDescription: "Services defined in the content, by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"image": schema.StringAttribute{
+							MarkdownDescription: "The docker image of the service, without the tag.",
+							Computed:            true,
+						},
+						"tag": schema.StringAttribute{
+							MarkdownDescription: "The tag of docker image. Defaults to latest.",
+							Computed:            true,
+						},
+						"ports": schema.ListNestedAttribute{
+							MarkdownDescription: "The list of port mappings.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"container_port": schema.Int64Attribute{
+										MarkdownDescription: "Container port that will be exposed.",
+										Computed:            true,
+									},
+									"exposed_port": schema.Int64Attribute{
+										MarkdownDescription: "Published port of the mapping. Null when the port is not published.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+						"env": schema.ListNestedAttribute{
+							MarkdownDescription: "The list of environment variables, sorted by key for compose files.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"key": schema.StringAttribute{
+										MarkdownDescription: "Environment variable key.",
+										Computed:            true,
+									},
+									"value": schema.StringAttribute{
+										MarkdownDescription: "Environment variable value.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+						"commands": schema.ListAttribute{
+							MarkdownDescription: "List of executables for docker CMD command. Read from entrypoint in compose files and command in SDL.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"args": schema.ListAttribute{
+							MarkdownDescription: "List of params for docker CMD command. Read from command in compose files and args in SDL.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"cpu": schema.StringAttribute{
+							MarkdownDescription: "Service CPU in cores. Null when the file doesn't limit it.",
+							Computed:            true,
+						},
+						"memory": schema.StringAttribute{
+							MarkdownDescription: "Service Memory in GB. Null when the file doesn't limit it.",
+							Computed:            true,
+						},
+						"storage": schema.Int64Attribute{
+							MarkdownDescription: "Service storage in GB, rounded up. Only set for SDL, where persistent volumes are left out.",
+							Computed:            true,
+						},
+						"replicas": schema.Int64Attribute{
+							MarkdownDescription: "Number of service replicas. Defaults to 1.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ComposeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read compose data source.")
+
+	var state ComposeDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	services, format, err := parseComposeFile(state.Content.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Unable to parse compose file.",
+			err.Error(),
+		)
+		return
+	}
+
+	hash := sha256.Sum256([]byte(state.Content.ValueString()))
+
+	state.ID = types.StringValue(hex.EncodeToString(hash[:]))
+	state.Format = types.StringValue(format)
+	state.Services = make(map[string]ComposeServiceModel, len(services))
+
+	for name, service := range services {
+		state.Services[name] = mapComposeServiceToModel(service)
+
+		if len(service.InternalPorts) != 0 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("content"),
+				"Internal ports left out.",
+				fmt.Sprintf("Ports %s of service %s are not exposed globally in the SDL. They are left out of ports, since every port of spheron_instance is public.", formatPorts(service.InternalPorts), name),
+			)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading compose data source", map[string]any{"success": true})
+}
+
+func mapComposeServiceToModel(service composeService) ComposeServiceModel {
+	model := ComposeServiceModel{
+		Image:    types.StringValue(service.Image),
+		Tag:      types.StringValue(service.Tag),
+		Commands: service.Commands,
+		Args:     service.Args,
+		Cpu:      types.StringNull(),
+		Memory:   types.StringNull(),
+		Storage:  types.Int64Null(),
+		Replicas: types.Int64Value(service.Replicas),
+	}
+
+	for _, port := range service.Ports {
+		exposedPort := types.Int64Null()
+		if port.ExposedPort != 0 {
+			exposedPort = types.Int64Value(port.ExposedPort)
+		}
+		model.Ports = append(model.Ports, Port{ContainerPort: types.Int64Value(port.ContainerPort), ExposedPort: exposedPort})
+	}
+
+	for _, env := range service.Env {
+		model.Env = append(model.Env, Env{Key: types.StringValue(env.Key), Value: types.StringValue(env.Value)})
+	}
+
+	if service.Cpu != "" {
+		model.Cpu = types.StringValue(service.Cpu)
+	}
+	if service.Memory != "" {
+		model.Memory = types.StringValue(service.Memory)
+	}
+	if service.Storage != 0 {
+		model.Storage = types.Int64Value(service.Storage)
+	}
+
+	return model
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccComposeDataSource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+data "spheron_compose" "test" {
+  content = "services: ["
+}
+`,
+				ExpectError: regexp.MustCompile(`Unable to parse compose file`),
+			},
+			{
+				Config: testAccProviderConfig + testAccComposeDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.spheron_compose.test", "format", "compose"),
+					resource.TestCheckResourceAttr("data.spheron_compose.test", "services.%", "1"),
+					resource.TestCheckResourceAttr("data.spheron_compose.test", "services.web.image", "crccheck/hello-world"),
+					resource.TestCheckResourceAttr("data.spheron_compose.test", "services.web.tag", "v1"),
+					resource.TestCheckResourceAttr("data.spheron_compose.test", "services.web.memory", "2"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "image", "crccheck/hello-world"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "cpu", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "ports.0.container_port", "8000"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "ports.0.exposed_port", "80"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "env.#", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "args.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testAccComposeDataSourceConfig = `
+data "spheron_compose" "test" {
+  content = <<-EOT
+    services:
+      web:
+        image: crccheck/hello-world:v1
+        ports:
+          - "80:8000"
+        environment:
+          MODE: web
+        command: ["--port", "8000"]
+        deploy:
+          resources:
+            limits:
+              cpus: "1"
+              memory: 2G
+  EOT
+}
+
+locals {
+  web = data.spheron_compose.test.services["web"]
+}
+
+resource "spheron_instance" "test" {
+  image        = local.web.image
+  tag          = local.web.tag
+  cluster_name = "tf_test_compose"
+  region       = "any"
+  cpu          = local.web.cpu
+  memory       = local.web.memory
+  replicas     = local.web.replicas
+  storage      = 10
+  compute_type = "SPOT"
+
+  ports = local.web.ports
+  env   = local.web.env
+  args  = local.web.args
+}
+`
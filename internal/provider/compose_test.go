@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseComposeFile(t *testing.T) {
+	content := `
+services:
+  web:
+    image: registry.example.com:5000/team/web:1.4
+    ports:
+      - "80:8000"
+      - "127.0.0.1:9090:9000/tcp"
+      - "3000"
+      - target: 4000
+        published: 8080
+    environment:
+      MODE: web
+      DEBUG:
+    entrypoint: /bin/server
+    command: ["--port", "8000"]
+    deploy:
+      replicas: 2
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 512M
+  worker:
+    image: myorg/worker
+    environment:
+      - QUEUE=jobs
+      - EMPTY
+    command: run --verbose
+`
+
+	services, format, err := parseComposeFile(content)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if format != composeFormatCompose {
+		t.Errorf("expected compose format, got %s", format)
+	}
+
+	expected := map[string]composeService{
+		"web": {
+			Image: "registry.example.com:5000/team/web",
+			Tag:   "1.4",
+			Ports: []composePort{
+				{ContainerPort: 8000, ExposedPort: 80},
+				{ContainerPort: 9000, ExposedPort: 9090},
+				{ContainerPort: 3000},
+				{ContainerPort: 4000, ExposedPort: 8080},
+			},
+			Env:      []composeEnv{{Key: "DEBUG", Value: ""}, {Key: "MODE", Value: "web"}},
+			Commands: []string{"/bin/server"},
+			Args:     []string{"--port", "8000"},
+			Cpu:      "0.5",
+			Memory:   "0.5",
+			Replicas: 2,
+		},
+		"worker": {
+			Image:    "myorg/worker",
+			Tag:      "latest",
+			Env:      []composeEnv{{Key: "EMPTY", Value: ""}, {Key: "QUEUE", Value: "jobs"}},
+			Args:     []string{"run", "--verbose"},
+			Replicas: 1,
+		},
+	}
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected %+v, got %+v", expected, services)
+	}
+}
+
+func TestParseComposeFile_sdl(t *testing.T) {
+	content := `
+version: "2.0"
+services:
+  web:
+    image: crccheck/hello-world:v1
+    expose:
+      - port: 8000
+        as: 80
+        to:
+          - global: true
+      - port: 9000
+      - port: 9100
+        to:
+          - service: worker
+    env:
+      - MODE=web
+    command:
+      - sh
+      - -c
+    args:
+      - start
+profiles:
+  compute:
+    web-profile:
+      resources:
+        cpu:
+          units: 500m
+        memory:
+          size: 1.5Gi
+        storage:
+          - size: 10Gi
+          - name: data
+            size: 100Gi
+            attributes:
+              persistent: true
+          - size: 512Mi
+deployment:
+  web:
+    dcloud:
+      profile: web-profile
+      count: 3
+`
+
+	services, format, err := parseComposeFile(content)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if format != composeFormatSDL {
+		t.Errorf("expected sdl format, got %s", format)
+	}
+
+	expected := composeService{
+		Image:         "crccheck/hello-world",
+		Tag:           "v1",
+		Ports:         []composePort{{ContainerPort: 8000, ExposedPort: 80}},
+		Env:           []composeEnv{{Key: "MODE", Value: "web"}},
+		Commands:      []string{"sh", "-c"},
+		Args:          []string{"start"},
+		Cpu:           "0.5",
+		Memory:        "1.5",
+		Storage:       11,
+		Replicas:      3,
+		InternalPorts: []int64{9000, 9100},
+	}
+	if !reflect.DeepEqual(services["web"], expected) {
+		t.Errorf("expected %+v, got %+v", expected, services["web"])
+	}
+}
+
+func TestParseComposeFile_errors(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		wantErr string
+	}{
+		"invalid yaml": {
+			content: "services: [",
+			wantErr: "Unable to parse YAML",
+		},
+		"no services": {
+			content: "version: '3'",
+			wantErr: "File doesn't define any services.",
+		},
+		"no image": {
+			content: "services:\n  web:\n    build: .",
+			wantErr: "Service web: Service has no image.",
+		},
+		"digest": {
+			content: "services:\n  web:\n    image: nginx@sha256:abc",
+			wantErr: "pinned by digest",
+		},
+		"port range": {
+			content: "services:\n  web:\n    image: nginx\n    ports:\n      - \"8000-8001:8000\"",
+			wantErr: "Port ranges are not supported.",
+		},
+		"memory unit": {
+			content: "services:\n  web:\n    image: nginx\n    deploy:\n      resources:\n        limits:\n          memory: 1Q",
+			wantErr: "Unknown unit Q.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := parseComposeFile(tc.content)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	testCases := map[string][]string{
+		`run --verbose`:                {"run", "--verbose"},
+		`  sh   -c "echo hi && run"  `: {"sh", "-c", "echo hi && run"},
+		`echo 'single "quoted"' text`:  {"echo", `single "quoted"`, "text"},
+		`echo "a \"b\" \$HOME \x"`:     {"echo", `a "b" $HOME \x`},
+		`echo escaped\ space "" end`:   {"echo", "escaped space", "", "end"},
+		`echo con"cat"'enated'`:        {"echo", "concatenated"},
+		"echo line\\\ncontinued":       {"echo", "linecontinued"},
+		``:                             nil,
+	}
+
+	for command, expected := range testCases {
+		words, err := splitShellWords(command)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %s", command, err)
+			continue
+		}
+		if !reflect.DeepEqual(words, expected) {
+			t.Errorf("expected %q to be split into %q, got %q", command, expected, words)
+		}
+	}
+
+	for _, command := range []string{`echo "unterminated`, `echo 'unterminated`} {
+		if _, err := splitShellWords(command); err == nil || !strings.Contains(err.Error(), "Unterminated") {
+			t.Errorf("expected unterminated quote error for %q, got %v", command, err)
+		}
+	}
+}
+
+func TestNormalizeResources(t *testing.T) {
+	cpuCases := map[string]string{"1": "1", "0.50": "0.5", "2000m": "2", "250m": "0.25"}
+	for value, expected := range cpuCases {
+		if actual, err := normalizeCpu(value); err != nil || actual != expected {
+			t.Errorf("expected cpu %s to be %s, got %s, %v", value, expected, actual, err)
+		}
+	}
+
+	memoryCases := map[string]string{"512Mi": "0.5", "512m": "0.5", "1.5Gi": "1.5", "2G": "2", "1gb": "1", "1Ti": "1024"}
+	for value, expected := range memoryCases {
+		if actual, err := normalizeMemory(value); err != nil || actual != expected {
+			t.Errorf("expected memory %s to be %s, got %s, %v", value, expected, actual, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const defaultProfileName = "default"
+
+type credentialsFile struct {
+	Profiles map[string]credentialsProfile `json:"profiles"`
+}
+
+type credentialsProfile struct {
+	Token  string `json:"token"`
+	ApiUrl string `json:"api_url"`
+}
+
+func getCredentialsFilePath() (string, error) {
+	if path := os.Getenv("SPHERON_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".spheron", "config.json"), nil
+}
+
+// loadCredentialsProfile reads the named profile from the credentials file. A missing file or
+// profile is only reported as an error when required is set.
+func loadCredentialsProfile(path string, name string, required bool) (credentialsProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return credentialsProfile{}, nil
+		}
+		return credentialsProfile{}, fmt.Errorf("Unable to read credentials file %s: %s", path, err)
+	}
+
+	var file credentialsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return credentialsProfile{}, fmt.Errorf("Unable to parse credentials file %s: %s", path, err)
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok && required {
+		names := make([]string, 0, len(file.Profiles))
+		for profileName := range file.Profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+
+		return credentialsProfile{}, fmt.Errorf("Profile %q not found in credentials file %s. Available profiles are: %s", name, path, strings.Join(names, ", "))
+	}
+
+	return profile, nil
+}
+
+func runTokenCommand(ctx context.Context, command []string) (string, error) {
+	if len(command) == 0 || command[0] == "" {
+		return "", errors.New("Token command is empty.")
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Token command %s failed: %s %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("Token command %s returned an empty token.", command[0])
+	}
+
+	return token, nil
+}
+
+// resolveCredentials returns the token and API URL to use, in order of precedence: the token attribute,
+// the token_command attribute, the profile attribute, the SPHERON_TOKEN env variable and finally the
+// profile named by SPHERON_PROFILE, defaulting to the default profile.
+func resolveCredentials(ctx context.Context, config SpheronProviderModel) (string, string, error) {
+	var profile credentialsProfile
+	var token string
+	var err error
+
+	switch {
+	case !config.Token.IsNull():
+		token = config.Token.ValueString()
+	case !config.TokenCommand.IsNull():
+		var command []string
+		if diags := config.TokenCommand.ElementsAs(ctx, &command, false); diags.HasError() {
+			return "", "", errors.New("Unable to read token command.")
+		}
+
+		token, err = runTokenCommand(ctx, command)
+	case !config.Profile.IsNull():
+		profile, err = loadProfile(config.Profile.ValueString(), true)
+		token = profile.Token
+	case os.Getenv("SPHERON_TOKEN") != "":
+		token = os.Getenv("SPHERON_TOKEN")
+	default:
+		name := os.Getenv("SPHERON_PROFILE")
+		profile, err = loadProfile(name, name != "")
+		token = profile.Token
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	apiUrl := os.Getenv("SPHERON_API_URL")
+	if !config.ApiUrl.IsNull() && !config.ApiUrl.IsUnknown() {
+		apiUrl = config.ApiUrl.ValueString()
+	}
+	if apiUrl == "" {
+		apiUrl = profile.ApiUrl
+	}
+
+	return token, apiUrl, nil
+}
+
+func loadProfile(name string, required bool) (credentialsProfile, error) {
+	if name == "" {
+		name = defaultProfileName
+	}
+
+	path, err := getCredentialsFilePath()
+	if err != nil {
+		if required {
+			return credentialsProfile{}, err
+		}
+		return credentialsProfile{}, nil
+	}
+
+	return loadCredentialsProfile(path, name, required)
+}
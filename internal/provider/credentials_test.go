@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("unable to write credentials file: %s", err)
+	}
+
+	return path
+}
+
+func TestLoadCredentialsProfile(t *testing.T) {
+	path := writeCredentialsFile(t, `{"profiles": {"default": {"token": "default-token"}, "ci": {"token": "ci-token", "api_url": "https://ci.example.com"}}}`)
+
+	profile, err := loadCredentialsProfile(path, "ci", true)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if profile.Token != "ci-token" || profile.ApiUrl != "https://ci.example.com" {
+		t.Errorf("unexpected profile %+v", profile)
+	}
+
+	_, err = loadCredentialsProfile(path, "prod", true)
+	if err == nil || !strings.Contains(err.Error(), "Available profiles are: ci, default") {
+		t.Errorf("expected missing profile error, got %v", err)
+	}
+
+	profile, err = loadCredentialsProfile(path, "prod", false)
+	if err != nil || profile != (credentialsProfile{}) {
+		t.Errorf("expected empty optional profile, got %+v, %v", profile, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if _, err := loadCredentialsProfile(missing, "default", false); err != nil {
+		t.Errorf("expected missing optional file to be ignored, got %v", err)
+	}
+	if _, err := loadCredentialsProfile(missing, "default", true); err == nil {
+		t.Error("expected error for missing required file")
+	}
+
+	invalid := writeCredentialsFile(t, `{"profiles": [`)
+	if _, err := loadCredentialsProfile(invalid, "default", false); err == nil || !strings.Contains(err.Error(), "Unable to parse credentials file") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	ctx := context.Background()
+
+	token, err := runTokenCommand(ctx, []string{"echo", " command-token "})
+	if err != nil || token != "command-token" {
+		t.Errorf("expected command-token, got %q, %v", token, err)
+	}
+
+	if _, err := runTokenCommand(ctx, []string{"true"}); err == nil || !strings.Contains(err.Error(), "empty token") {
+		t.Errorf("expected empty token error, got %v", err)
+	}
+
+	if _, err := runTokenCommand(ctx, []string{"sh", "-c", "echo denied >&2; exit 1"}); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected command error with stderr, got %v", err)
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv("SPHERON_CONFIG_FILE", writeCredentialsFile(t, `{"profiles": {"default": {"token": "default-token"}, "ci": {"token": "ci-token", "api_url": "https://ci.example.com"}}}`))
+	t.Setenv("SPHERON_API_URL", "")
+
+	command := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("echo"), types.StringValue("command-token")})
+	model := func(token types.String, tokenCommand types.List, profile types.String) SpheronProviderModel {
+		return SpheronProviderModel{Token: token, TokenCommand: tokenCommand, Profile: profile, ApiUrl: types.StringNull()}
+	}
+	noCommand := types.ListNull(types.StringType)
+
+	testCases := map[string]struct {
+		config         SpheronProviderModel
+		envToken       string
+		envProfile     string
+		expectedToken  string
+		expectedApiUrl string
+	}{
+		"token attribute": {
+			config:        model(types.StringValue("attribute-token"), command, types.StringValue("ci")),
+			envToken:      "env-token",
+			expectedToken: "attribute-token",
+		},
+		"token command": {
+			config:        model(types.StringNull(), command, types.StringValue("ci")),
+			envToken:      "env-token",
+			expectedToken: "command-token",
+		},
+		"profile attribute": {
+			config:         model(types.StringNull(), noCommand, types.StringValue("ci")),
+			envToken:       "env-token",
+			expectedToken:  "ci-token",
+			expectedApiUrl: "https://ci.example.com",
+		},
+		"token env": {
+			config:        model(types.StringNull(), noCommand, types.StringNull()),
+			envToken:      "env-token",
+			envProfile:    "ci",
+			expectedToken: "env-token",
+		},
+		"profile env": {
+			config:         model(types.StringNull(), noCommand, types.StringNull()),
+			envProfile:     "ci",
+			expectedToken:  "ci-token",
+			expectedApiUrl: "https://ci.example.com",
+		},
+		"default profile": {
+			config:        model(types.StringNull(), noCommand, types.StringNull()),
+			expectedToken: "default-token",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("SPHERON_TOKEN", tc.envToken)
+			t.Setenv("SPHERON_PROFILE", tc.envProfile)
+
+			token, apiUrl, err := resolveCredentials(ctx, tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if token != tc.expectedToken || apiUrl != tc.expectedApiUrl {
+				t.Errorf("expected %q and %q, got %q and %q", tc.expectedToken, tc.expectedApiUrl, token, apiUrl)
+			}
+		})
+	}
+}
+
+func TestAccProvider_credentials(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	t.Setenv("SPHERON_TOKEN", "")
+	t.Setenv("SPHERON_CONFIG_FILE", writeCredentialsFile(t, fmt.Sprintf(`{"profiles": {"ci": {"token": %q}}}`, server.Token)))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "spheron" {
+  profile = "prod"
+}
+
+data "spheron_organization" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`Profile "prod" not found in credentials file`),
+			},
+			{
+				Config: `
+provider "spheron" {}
+
+data "spheron_organization" "test" {}
+`,
+				ExpectError: regexp.MustCompile(`Missing Spheron access token`),
+			},
+			{
+				Config: `
+provider "spheron" {
+  profile = "ci"
+}
+
+data "spheron_organization" "test" {}
+`,
+				Check: resource.TestCheckResourceAttr("data.spheron_organization.test", "id", "org-1"),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "spheron" {
+  token_command = ["echo", %q]
+}
+
+data "spheron_organization" "test" {}
+`, server.Token),
+				Check: resource.TestCheckResourceAttr("data.spheron_organization.test", "id", "org-1"),
+			},
+		},
+	})
+}
@@ -0,0 +1,1040 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &DeploymentResource{}
+var _ resource.ResourceWithImportState = &DeploymentResource{}
+var _ resource.ResourceWithModifyPlan = &DeploymentResource{}
+
+func NewDeploymentResource() resource.Resource {
+	return &DeploymentResource{}
+}
+
+type DeploymentResource struct {
+	client *client.SpheronApi
+	budget *spendBudget
+	token  *tokenCheck
+}
+
+type DeploymentResourceModel struct {
+	Id                    types.String             `tfsdk:"id"`
+	Name                  types.String             `tfsdk:"name"`
+	Region                types.String             `tfsdk:"region"`
+	ComputeType           types.String             `tfsdk:"compute_type"`
+	EstimatedCostPerHour  types.Float64            `tfsdk:"estimated_cost_per_hour"`
+	EstimatedCostPerMonth types.Float64            `tfsdk:"estimated_cost_per_month"`
+	Services              []DeploymentServiceModel `tfsdk:"service"`
+}
+
+type DeploymentServiceModel struct {
+	Name         types.String `tfsdk:"name"`
+	Image        types.String `tfsdk:"image"`
+	Tag          types.String `tfsdk:"tag"`
+	Replicas     types.Int64  `tfsdk:"replicas"`
+	Cpu          types.String `tfsdk:"cpu"`
+	Memory       types.String `tfsdk:"memory"`
+	MachineImage types.String `tfsdk:"machine_image"`
+	Storage      types.Int64  `tfsdk:"storage"`
+	Ports        []Port       `tfsdk:"ports"`
+	Env          []Env        `tfsdk:"env"`
+	EnvSecret    []Env        `tfsdk:"env_secret"`
+	Commands     []string     `tfsdk:"commands"`
+	Args         []string     `tfsdk:"args"`
+	DependsOn    []string     `tfsdk:"depends_on"`
+	Id           types.String `tfsdk:"id"`
+	Host         types.String `tfsdk:"host"`
+}
+
+const deploymentCloseTimeout = 5 * time.Minute
+
+func (r *DeploymentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func (r *DeploymentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deployment resource. Every service is deployed as a separate instance in the cluster. There is no private network between the services: a service reaches its dependencies only through the ports they expose on their provider host, so those ports are reachable from the internet.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Id of the cluster the services are deployed to.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the cluster the services are deployed to.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Region to which to deploy the services.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"compute_type": schema.StringAttribute{
+				MarkdownDescription: "Compute type of the services, determining how hardware resources will scale. Available values [SPOT, DEMAND]. Defaults to SPOT.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("SPOT"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfComputeTypeChanged,
+						"Changing the compute type requires new instances.",
+						"Changing the compute type requires new instances.",
+					),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						"SPOT",
+						"DEMAND",
+					),
+				},
+			},
+			"estimated_cost_per_hour": schema.Float64Attribute{
+				MarkdownDescription: "Estimated cost of all services in USD per hour, calculated during plan.",
+				Computed:            true,
+			},
+			"estimated_cost_per_month": schema.Float64Attribute{
+				MarkdownDescription: "Estimated cost of all services in USD per month, calculated during plan.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"service": schema.ListNestedBlock{
+				MarkdownDescription: "Service deployed as part of the deployment.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the service, unique within the deployment.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "must start with a lowercase letter and contain only lowercase letters, digits and dashes"),
+							},
+						},
+						"image": schema.StringAttribute{
+							MarkdownDescription: "The docker image to deploy. Changing it redeploys the service.",
+							Required:            true,
+						},
+						"tag": schema.StringAttribute{
+							MarkdownDescription: "The tag of docker image.",
+							Required:            true,
+						},
+						"replicas": schema.Int64Attribute{
+							MarkdownDescription: "Number of service replicas. Changing it redeploys the service. Defaults to 1.",
+							Optional:            true,
+							Computed:            true,
+							Default:             int64default.StaticInt64(1),
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+								int64validator.AtMost(20),
+							},
+						},
+						"cpu": schema.StringAttribute{
+							MarkdownDescription: "Service CPU in cores, like 0.5 or 2, or in millicores, like 500m. Changing it to a different size redeploys the service.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(cpuPattern, "must be a number of cores, like 0.5, or millicores, like 500m"),
+								positiveSize{parse: parseCpu},
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("memory")),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
+							},
+						},
+						"memory": schema.StringAttribute{
+							MarkdownDescription: "Service Memory in GB, like 2, or with a unit, like 512Mi or 1.5Gi. Changing it to a different size redeploys the service.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(memoryPattern, "must be a size in GB, like 2, or with a unit, like 512Mi or 1.5Gi"),
+								positiveSize{parse: parseMemory},
+								stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cpu")),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
+							},
+						},
+						"machine_image": schema.StringAttribute{
+							MarkdownDescription: "Machine image name which should be used for deploying the service. Changing it redeploys the service.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("cpu")),
+							},
+						},
+						"storage": schema.Int64Attribute{
+							MarkdownDescription: "Service storage in GB. Value cannot exceed 1024GB. Changing it redeploys the service.",
+							Required:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+								int64validator.AtMost(1024),
+							},
+						},
+						"ports": schema.ListNestedAttribute{
+							MarkdownDescription: "The list of port mappings. Every port is exposed on the provider host, including ports that are only used by other services of the deployment. Changing it redeploys the service.",
+							Optional:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"container_port": schema.Int64Attribute{
+										MarkdownDescription: "Container port that will be exposed.",
+										Required:            true,
+									},
+									"exposed_port": schema.Int64Attribute{
+										MarkdownDescription: "The port container port will be exposed to. Currently only posible to expose to port 80. Leave empty to map to random value. Exposed port will be know and available for use after the deployment.",
+										Optional:            true,
+										Computed:            true,
+									},
+								},
+							},
+						},
+						"env": schema.SetNestedAttribute{
+							MarkdownDescription: "The list of environmetnt variables.",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"key": schema.StringAttribute{
+										MarkdownDescription: "Environment variable key.",
+										Required:            true,
+									},
+									"value": schema.StringAttribute{
+										MarkdownDescription: "Environment variable value.",
+										Required:            true,
+									},
+								},
+							},
+							Optional: true,
+						},
+						"env_secret": schema.SetNestedAttribute{
+							MarkdownDescription: "The list of secret environmetnt variables.",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"key": schema.StringAttribute{
+										MarkdownDescription: "Environment variable key.",
+										Required:            true,
+									},
+									"value": schema.StringAttribute{
+										MarkdownDescription: "Environment variable value.",
+										Required:            true,
+										Sensitive:           true,
+									},
+								},
+							},
+							Optional: true,
+						},
+						"commands": schema.ListAttribute{
+							MarkdownDescription: "List of executables for docker CMD command.",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"args": schema.ListAttribute{
+							MarkdownDescription: "List of params for docker CMD command.",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"depends_on": schema.ListAttribute{
+							MarkdownDescription: "Names of services that are deployed before this one. Their provider host and exposed ports are passed to this service as environment variables.",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Id of the instance running the service.",
+							Computed:            true,
+						},
+						"host": schema.StringAttribute{
+							MarkdownDescription: "Provider host the service ports are exposed on.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *DeploymentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = data.client
+	r.budget = data.budget
+	r.token = data.token
+}
+
+func (r *DeploymentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan DeploymentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = maskSensitiveValues(ctx, getDeploymentSecretValues(plan.Services)...)
+
+	organization, err := r.client.GetOrganization(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get organization",
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.deployServices(ctx, organization.ID, &plan, map[string]DeploymentServiceModel{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Created deployment resource", map[string]any{"success": true})
+}
+
+func (r *DeploymentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		tflog.Debug(ctx, "Spheron client is not configured yet, keeping prior state")
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state DeploymentResourceModel
+	tflog.Debug(ctx, "Preparing to read deployment resource")
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = maskSensitiveValues(ctx, getDeploymentSecretValues(state.Services)...)
+
+	if state.Name.IsNull() {
+		resp.Diagnostics.Append(r.readImportedDeployment(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	services := make([]DeploymentServiceModel, 0, len(state.Services))
+	for _, service := range state.Services {
+		instance, err := r.client.GetClusterInstance(ctx, service.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Coudnt fetch instance by provided id.",
+				err.Error(),
+			)
+			return
+		}
+
+		if instance.State == "Closed" {
+			resp.Diagnostics.AddWarning("Service is closed", fmt.Sprintf("Instance %s of service %s is closed. Applying will redeploy the service.", instance.ID, service.Name.ValueString()))
+			continue
+		}
+
+		order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Instance doesn't have provisioned deployments.",
+				err.Error(),
+			)
+			return
+		}
+
+		services = append(services, mapClientOrderToDeploymentService(service, order))
+	}
+
+	if len(services) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Services = services
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// readImportedDeployment reads the cluster, region and service dependencies of an imported deployment, which
+// only has the names and instance ids of its services.
+func (r *DeploymentResource) readImportedDeployment(ctx context.Context, state *DeploymentResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	envs := make([][]client.Env, len(state.Services))
+	for i, service := range state.Services {
+		instance, err := r.client.GetClusterInstance(ctx, service.Id.ValueString())
+		if err != nil {
+			diags.AddError("Coudnt fetch instance by provided id.", err.Error())
+			return diags
+		}
+
+		order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+		if err != nil {
+			diags.AddError("Instance doesn't have provisioned deployments.", err.Error())
+			return diags
+		}
+
+		if order.ClusterInstanceConfiguration != nil {
+			state.Region = types.StringValue(order.ClusterInstanceConfiguration.Region)
+			envs[i] = order.ClusterInstanceConfiguration.Env
+		}
+
+		cluster, err := r.client.GetCluster(ctx, instance.Cluster)
+		if err != nil {
+			diags.AddError("Instance cluster not found.", err.Error())
+			return diags
+		}
+
+		state.Id = types.StringValue(cluster.ID)
+		state.Name = types.StringValue(cluster.Name)
+	}
+
+	// Dependencies aren't stored on the instances, but a service has the discovery variables of every
+	// service it depends on.
+	for i := range state.Services {
+		for _, dependency := range state.Services {
+			if dependency.Name.Equal(state.Services[i].Name) {
+				continue
+			}
+
+			for _, env := range envs[i] {
+				if key, _ := splitClientEnv(env.Value); !env.IsSecret && key == getServiceDiscoveryEnvPrefix(dependency.Name.ValueString())+"_HOST" {
+					state.Services[i].DependsOn = append(state.Services[i].DependsOn, dependency.Name.ValueString())
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
+func (r *DeploymentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state DeploymentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = maskSensitiveValues(ctx, append(getDeploymentSecretValues(plan.Services), getDeploymentSecretValues(state.Services)...)...)
+
+	organization, err := r.client.GetOrganization(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get organization",
+			err.Error(),
+		)
+		return
+	}
+
+	planned := map[string]DeploymentServiceModel{}
+	for _, service := range plan.Services {
+		planned[service.Name.ValueString()] = service
+	}
+
+	// Services that need to be redeployed get a new instance, the rest are updated in place. Instances of
+	// replaced and removed services are closed once all services are deployed, so a failed deployment
+	// leaves them running.
+	kept := map[string]DeploymentServiceModel{}
+	var closed []DeploymentServiceModel
+	for _, service := range state.Services {
+		plannedService, ok := planned[service.Name.ValueString()]
+		if ok && !deploymentServiceRequiresReplace(plannedService, service) {
+			kept[service.Name.ValueString()] = service
+			continue
+		}
+
+		closed = append(closed, service)
+	}
+
+	resp.Diagnostics.Append(r.deployServices(ctx, organization.ID, &plan, kept)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, service := range closed {
+		resp.Diagnostics.Append(destroyClusterInstance(ctx, r.client, service.Id.ValueString(), destroyInstanceOptions{timeout: deploymentCloseTimeout})...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Updated deployment resource", map[string]any{"success": true})
+}
+
+func (r *DeploymentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var state DeploymentResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		for _, service := range state.Services {
+			r.budget.release(service.Id)
+		}
+		return
+	}
+
+	var plan, state DeploymentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := getServiceDeploymentWaves(plan.Services); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("service"), "Invalid service dependencies.", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(validateServiceDiscoveryEnvs(plan.Services)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.checkDeployPermission(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateInstanceCatalog(ctx, r.client, plan.Region, state.Region, types.StringNull(), types.StringNull())...)
+	resp.Diagnostics.Append(r.validateServiceMachineImages(ctx, plan.Services, state.Services)...)
+	resp.Diagnostics.Append(r.validateServiceSizes(ctx, plan.Services, state.Services)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Services that are kept keep their instance, so their computed values are known from state.
+	stateServices := map[string]DeploymentServiceModel{}
+	for _, service := range state.Services {
+		stateServices[service.Name.ValueString()] = service
+	}
+
+	plannedServices := map[string]bool{}
+	for _, service := range plan.Services {
+		plannedServices[service.Name.ValueString()] = true
+	}
+	for _, service := range state.Services {
+		if !plannedServices[service.Name.ValueString()] {
+			r.budget.release(service.Id)
+		}
+	}
+
+	specsChanged := req.State.Raw.IsNull() || len(plan.Services) != len(state.Services) ||
+		!plan.Region.Equal(state.Region) || !plan.ComputeType.Equal(state.ComputeType)
+
+	for i, service := range plan.Services {
+		stateService, ok := stateServices[service.Name.ValueString()]
+		if !ok || deploymentServiceRequiresReplace(service, stateService) {
+			specsChanged = true
+			continue
+		}
+
+		plan.Services[i].Id = stateService.Id
+		plan.Services[i].Host = stateService.Host
+		plan.Services[i].Ports = mergeDeploymentPorts(service.Ports, stateService.Ports)
+	}
+
+	costPerHour, costPerMonth := types.Float64Value(0), types.Float64Value(0)
+	for _, service := range plan.Services {
+		serviceCostPerHour, serviceCostPerMonth := types.Float64Unknown(), types.Float64Unknown()
+
+		priceRequest, ok := getInstancePriceRequest(ctx, plan.Region, service.MachineImage, service.Cpu, service.Memory, service.Storage, service.Replicas, nil, types.ObjectNull(gpuAttrTypes), plan.ComputeType.ValueString() == "DEMAND")
+		if ok && !plan.ComputeType.IsUnknown() {
+			var diags diag.Diagnostics
+
+			serviceCostPerHour, serviceCostPerMonth, diags = estimateInstanceCost(ctx, r.client, priceRequest)
+			resp.Diagnostics.Append(diags...)
+		}
+
+		costPerHour = addFloat64Values(costPerHour, serviceCostPerHour)
+		costPerMonth = addFloat64Values(costPerMonth, serviceCostPerMonth)
+
+		resp.Diagnostics.Append(r.budget.reserve(ctx, stateServices[service.Name.ValueString()].Id, fmt.Sprintf("%s/%s", plan.Name.ValueString(), service.Name.ValueString()), serviceCostPerHour)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if specsChanged {
+		plan.EstimatedCostPerHour = costPerHour
+		plan.EstimatedCostPerMonth = costPerMonth
+	} else {
+		plan.EstimatedCostPerHour = state.EstimatedCostPerHour
+		plan.EstimatedCostPerMonth = state.EstimatedCostPerMonth
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *DeploymentResource) validateServiceMachineImages(ctx context.Context, services []DeploymentServiceModel, stateServices []DeploymentServiceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var machines []client.ComputeMachine
+
+	for i, service := range services {
+		stateMachineImage := types.StringNull()
+		for _, stateService := range stateServices {
+			if stateService.Name.Equal(service.Name) {
+				stateMachineImage = stateService.MachineImage
+			}
+		}
+
+		if !isPlannedChange(service.MachineImage, stateMachineImage) || service.MachineImage.IsNull() {
+			continue
+		}
+
+		attributePath := path.Root("service").AtListIndex(i).AtName("machine_image")
+
+		if machines == nil {
+			var err error
+			machines, err = r.client.GetComputeMachines(ctx)
+			if err != nil {
+				diags.AddAttributeWarning(attributePath, "Unable to validate machine image.", err.Error())
+				return diags
+			}
+		}
+
+		if err := validateMachineImage(machines, service.MachineImage.ValueString()); err != nil {
+			diags.AddAttributeError(attributePath, "Invalid machine image.", err.Error())
+		}
+	}
+
+	return diags
+}
+
+func (r *DeploymentResource) validateServiceSizes(ctx context.Context, services []DeploymentServiceModel, stateServices []DeploymentServiceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, service := range services {
+		stateCpu, stateMemory := types.StringNull(), types.StringNull()
+		for _, stateService := range stateServices {
+			if stateService.Name.Equal(service.Name) {
+				stateCpu, stateMemory = stateService.Cpu, stateService.Memory
+			}
+		}
+
+		if !isPlannedChange(service.Cpu, stateCpu) && !isPlannedChange(service.Memory, stateMemory) {
+			continue
+		}
+
+		servicePath := path.Root("service").AtListIndex(i)
+		diags.Append(validateInstanceSize(ctx, r.client, service.Cpu, service.Memory, servicePath.AtName("cpu"), servicePath.AtName("memory"))...)
+	}
+
+	return diags
+}
+
+func (r *DeploymentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	pairs := strings.Split(req.ID, ",")
+
+	services := make([]DeploymentServiceModel, 0, len(pairs))
+	for _, pair := range pairs {
+		name, id, _ := strings.Cut(pair, "=")
+		if name == "" || id == "" {
+			resp.Diagnostics.AddError(
+				"Unexpected import identifier",
+				fmt.Sprintf("Expected import identifier with format: service_name=instance_id,service_name=instance_id. Got: %q", req.ID),
+			)
+			return
+		}
+
+		services = append(services, DeploymentServiceModel{Name: types.StringValue(name), Id: types.StringValue(id)})
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("service"), services)...)
+}
+
+func (r *DeploymentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Preparing to delete deployment resource")
+	var state DeploymentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for i := len(state.Services) - 1; i >= 0; i-- {
+		resp.Diagnostics.Append(destroyClusterInstance(ctx, r.client, state.Services[i].Id.ValueString(), destroyInstanceOptions{timeout: deploymentCloseTimeout})...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Debug(ctx, "Deployment closed", map[string]any{"success": true})
+}
+
+type deployedService struct {
+	host  string
+	ports []client.Port
+}
+
+type pendingDeployment struct {
+	service string
+	topic   string
+}
+
+// deployServices deploys the planned services wave by wave, waiting for every wave at once. Services in
+// existing are updated in place, the others are created. Instances created by a failed deployment are closed.
+func (r *DeploymentResource) deployServices(ctx context.Context, organizationID string, plan *DeploymentResourceModel, existing map[string]DeploymentServiceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	waves, err := getServiceDeploymentWaves(plan.Services)
+	if err != nil {
+		diags.AddError("Invalid service dependencies.", err.Error())
+		return diags
+	}
+
+	indexes := map[string]int{}
+	for i, service := range plan.Services {
+		indexes[service.Name.ValueString()] = i
+	}
+
+	var created []string
+	closeCreated := func() {
+		for _, id := range created {
+			diags.Append(destroyClusterInstance(ctx, r.client, id, destroyInstanceOptions{timeout: deploymentCloseTimeout})...)
+		}
+	}
+
+	deployed := map[string]deployedService{}
+	for _, wave := range waves {
+		var pending []pendingDeployment
+
+		for _, name := range wave {
+			service := &plan.Services[indexes[name]]
+			envs := append(mapEnvsToClientEnvs(service.Env, false), getServiceDiscoveryEnvs(service.DependsOn, deployed)...)
+			envs = append(envs, mapEnvsToClientEnvs(service.EnvSecret, true)...)
+
+			if existingService, ok := existing[name]; ok {
+				service.Id = existingService.Id
+
+				topic, err := r.updateService(ctx, organizationID, *service, envs)
+				if err != nil {
+					diags.AddError("Unable to update service.", fmt.Sprintf("Service %s: %s", name, err.Error()))
+					closeCreated()
+					return diags
+				}
+				if topic != "" {
+					pending = append(pending, pendingDeployment{service: name, topic: topic})
+				}
+				continue
+			}
+
+			topicId := uuid.New()
+
+			response, err := r.client.CreateClusterInstance(ctx, getDeploymentServiceCreateRequest(organizationID, topicId.String(), *plan, *service, envs))
+			if err != nil {
+				diags.AddError("Unable to deploy service.", fmt.Sprintf("Service %s: %s", name, err.Error()))
+				closeCreated()
+				return diags
+			}
+
+			created = append(created, response.ClusterInstanceID)
+			service.Id = types.StringValue(response.ClusterInstanceID)
+			plan.Id = types.StringValue(response.ClusterID)
+			pending = append(pending, pendingDeployment{service: name, topic: topicId.String()})
+		}
+
+		if err := waitForDeployments(ctx, r.client, pending); err != nil {
+			diags.AddError("Deployment failed.", fmt.Sprintf("Deployment of cluster %s failed. %s", plan.Name.ValueString(), err.Error()))
+			closeCreated()
+			return diags
+		}
+
+		for _, name := range wave {
+			service := &plan.Services[indexes[name]]
+
+			instance, err := r.client.GetClusterInstance(ctx, service.Id.ValueString())
+			if err != nil {
+				diags.AddError("Coudnt fetch instance by provided id.", err.Error())
+				closeCreated()
+				return diags
+			}
+
+			order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+			if err != nil {
+				diags.AddError("Instance doesn't have provisioned deployments.", err.Error())
+				closeCreated()
+				return diags
+			}
+
+			plan.Id = types.StringValue(instance.Cluster)
+			service.Host = types.StringValue(getOrderProviderHost(order))
+			service.Ports = mapClientPortsToDeploymentPorts(order.ClusterInstanceConfiguration.Ports, service.Ports)
+
+			deployed[name] = deployedService{
+				host:  getOrderProviderHost(order),
+				ports: order.ClusterInstanceConfiguration.Ports,
+			}
+		}
+	}
+
+	return diags
+}
+
+// updateService redeploys the service when its tag, environment variables, commands or args changed, and
+// returns the topic to wait for. An empty topic means the service is up to date.
+func (r *DeploymentResource) updateService(ctx context.Context, organizationID string, service DeploymentServiceModel, envs []client.Env) (string, error) {
+	instance, err := r.client.GetClusterInstance(ctx, service.Id.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+	if err != nil {
+		return "", err
+	}
+
+	config := order.ClusterInstanceConfiguration
+	if config != nil && config.Tag == service.Tag.ValueString() && clientEnvsEqual(config.Env, envs) &&
+		reflect.DeepEqual(config.Command, service.Commands) && reflect.DeepEqual(config.Args, service.Args) {
+		return "", nil
+	}
+
+	topicId := uuid.New()
+
+	_, err = r.client.UpdateClusterInstance(ctx, service.Id.ValueString(), client.UpdateInstanceRequest{
+		Env:            envs,
+		Command:        service.Commands,
+		Args:           service.Args,
+		UniqueTopicID:  topicId.String(),
+		Tag:            service.Tag.ValueString(),
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return topicId.String(), nil
+}
+
+func getDeploymentServiceCreateRequest(organizationID string, topicID string, plan DeploymentResourceModel, service DeploymentServiceModel, envs []client.Env) client.CreateInstanceRequest {
+	instanceConfig := client.InstanceConfiguration{
+		Protocol:      client.ClusterProtocolAkash,
+		Image:         service.Image.ValueString(),
+		Tag:           service.Tag.ValueString(),
+		InstanceCount: int(service.Replicas.ValueInt64()),
+		Ports:         mapPortToPortModel(service.Ports),
+		Env:           envs,
+		Command:       service.Commands,
+		Args:          service.Args,
+		Region:        plan.Region.ValueString(),
+		CustomInstanceSpecs: client.CustomInstanceSpecs{
+			Storage: fmt.Sprintf("%dGi", int(service.Storage.ValueInt64())),
+		},
+	}
+
+	if service.MachineImage.IsNull() {
+		instanceConfig.CustomInstanceSpecs.CPU = formatCpu(service.Cpu.ValueString())
+		instanceConfig.CustomInstanceSpecs.Memory = formatMemory(service.Memory.ValueString())
+	} else {
+		instanceConfig.AkashMachineImageName = service.MachineImage.ValueString()
+	}
+
+	return client.CreateInstanceRequest{
+		OrganizationID:  organizationID,
+		UniqueTopicID:   topicID,
+		Configuration:   instanceConfig,
+		InstanceName:    service.Name.ValueString(),
+		ClusterURL:      service.Image.ValueString(),
+		ClusterProvider: "DOCKERHUB",
+		ClusterName:     plan.Name.ValueString(),
+		Scalable:        plan.ComputeType.ValueString() == "DEMAND",
+	}
+}
+
+// requiresReplaceIfComputeTypeChanged replaces the services when the compute type changes. Imported deployments
+// have no compute type, so it is set in place.
+func requiresReplaceIfComputeTypeChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.StateValue.IsNull()
+}
+
+func waitForDeployments(ctx context.Context, api *client.SpheronApi, pending []pendingDeployment) error {
+	errs := make([]error, len(pending))
+
+	var wg sync.WaitGroup
+	for i, deployment := range pending {
+		wg.Add(1)
+		go func(i int, deployment pendingDeployment) {
+			defer wg.Done()
+			_, errs[i] = api.WaitForDeployedEvent(ctx, deployment.topic)
+		}(i, deployment)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("Service %s was not deployed: %s", pending[i].service, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func mapClientOrderToDeploymentService(service DeploymentServiceModel, order client.InstanceOrder) DeploymentServiceModel {
+	config := order.ClusterInstanceConfiguration
+	if config == nil {
+		return service
+	}
+
+	envs := filterServiceDiscoveryEnvs(config.Env, service.DependsOn)
+
+	service.Image = types.StringValue(config.Image)
+	service.Tag = types.StringValue(config.Tag)
+	service.Replicas = types.Int64Value(int64(config.InstanceCount))
+	service.Env = mapClientEnvsToEnvs(envs, false)
+	service.EnvSecret = mapClientEnvsToEnvs(envs, true)
+	service.Commands = config.Command
+	service.Args = config.Args
+	service.Host = types.StringValue(getOrderProviderHost(order))
+	service.Ports = mapClientPortsToDeploymentPorts(config.Ports, service.Ports)
+
+	storage, _ := strconv.Atoi(RemoveGiSuffix(config.AgreedMachineImage.Storage))
+	service.Storage = types.Int64Value(int64(storage))
+
+	// Imported services have neither a machine image nor a size, so the agreed machine type decides.
+	if service.MachineImage.IsNull() && service.Cpu.IsNull() && config.AgreedMachineImage.MachineType != "Custom Plan" {
+		service.MachineImage = types.StringValue(config.AgreedMachineImage.MachineType)
+	} else if !service.MachineImage.IsNull() {
+		service.MachineImage = types.StringValue(config.AgreedMachineImage.MachineType)
+	} else {
+		service.Cpu = getCpuValue(service.Cpu, config.AgreedMachineImage.Cpu)
+		service.Memory = getMemoryValue(service.Memory, config.AgreedMachineImage.Memory)
+	}
+
+	return service
+}
+
+func getDeploymentSecretValues(services []DeploymentServiceModel) []string {
+	var values []string
+	for _, service := range services {
+		values = append(values, getEnvValues(service.EnvSecret)...)
+	}
+
+	return values
+}
+
+func isSameServiceSize(plan DeploymentServiceModel, state DeploymentServiceModel) bool {
+	if plan.Cpu.IsNull() || state.Cpu.IsNull() {
+		return plan.Cpu.Equal(state.Cpu) && plan.Memory.Equal(state.Memory)
+	}
+
+	return isSameSize(parseCpu, plan.Cpu.ValueString(), state.Cpu.ValueString()) &&
+		isSameSize(parseMemory, plan.Memory.ValueString(), state.Memory.ValueString())
+}
+
+// deploymentServiceRequiresReplace reports whether the planned service can't be updated in place and has
+// to be deployed to a new instance.
+func deploymentServiceRequiresReplace(plan DeploymentServiceModel, state DeploymentServiceModel) bool {
+	if !plan.Image.Equal(state.Image) || !plan.Replicas.Equal(state.Replicas) || !plan.Storage.Equal(state.Storage) ||
+		!plan.MachineImage.Equal(state.MachineImage) || !isSameServiceSize(plan, state) {
+		return true
+	}
+
+	if len(plan.Ports) != len(state.Ports) {
+		return true
+	}
+
+	for i, port := range plan.Ports {
+		if !port.ContainerPort.Equal(state.Ports[i].ContainerPort) {
+			return true
+		}
+		if !port.ExposedPort.IsUnknown() && !port.ExposedPort.Equal(state.Ports[i].ExposedPort) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergeDeploymentPorts fills the unknown exposed ports of a kept service from its state.
+func mergeDeploymentPorts(plan []Port, state []Port) []Port {
+	for i := range plan {
+		if plan[i].ExposedPort.IsUnknown() && i < len(state) {
+			plan[i].ExposedPort = state[i].ExposedPort
+		}
+	}
+
+	return plan
+}
+
+// mapClientPortsToDeploymentPorts maps the deployed ports to state, keeping ports null when none are configured.
+func mapClientPortsToDeploymentPorts(ports []client.Port, configured []Port) []Port {
+	if len(ports) == 0 && configured == nil {
+		return nil
+	}
+
+	return mapModelPortToPort(ports)
+}
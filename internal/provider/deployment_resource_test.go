@@ -0,0 +1,391 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+	"terraform-provider-spheron/internal/client/fake"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeploymentResource(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	var webID, workerID, redisID string
+
+	testAccCheckActiveOrder := func(attribute string, check func(order client.InstanceOrder) error) resource.TestCheckFunc {
+		return resource.TestCheckResourceAttrWith("spheron_deployment.test", attribute, func(value string) error {
+			instance, ok := server.Instance(value)
+			if !ok {
+				return fmt.Errorf("instance %s not found", value)
+			}
+			order, _ := server.Order(instance.ActiveOrder)
+			return check(order)
+		})
+	}
+
+	testAccCheckEnv := func(attribute string, expected string) resource.TestCheckFunc {
+		return testAccCheckActiveOrder(attribute, func(order client.InstanceOrder) error {
+			for _, env := range order.ClusterInstanceConfiguration.Env {
+				if env.Value == expected {
+					return nil
+				}
+			}
+			return fmt.Errorf("expected env %s, got %v", expected, order.ClusterInstanceConfiguration.Env)
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, id := range []string{webID, workerID, redisID} {
+				if instance, _ := server.Instance(id); instance.State != "Closed" {
+					return fmt.Errorf("expected instance %s to be closed, got %s", id, instance.State)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccDeploymentResourceCycleConfig(),
+				ExpectError: regexp.MustCompile(`Services worker, redis have circular dependencies`),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceConfig("latest", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_deployment.test", "id", "cluster-1"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "compute_type", "SPOT"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.#", "3"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.0.name", "web"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.0.ports.0.exposed_port", "80"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.0.host", fake.DefaultProviderHost),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.0.env.#", "1"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.1.replicas", "1"),
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.2.id", "instance-2"),
+					resource.TestCheckResourceAttrSet("spheron_deployment.test", "service.2.ports.0.exposed_port"),
+					resource.TestCheckResourceAttrSet("spheron_deployment.test", "estimated_cost_per_hour"),
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.0.id", func(value string) error {
+						webID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.1.id", func(value string) error {
+						workerID = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.2.id", func(value string) error {
+						redisID = value
+						return nil
+					}),
+					testAccCheckEnv("service.0.id", "SPHERON_SERVICE_REDIS_HOST="+fake.DefaultProviderHost),
+					testAccCheckEnv("service.1.id", "SPHERON_SERVICE_REDIS_HOST="+fake.DefaultProviderHost),
+				),
+			},
+			{
+				ResourceName:            "spheron_deployment.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateIdFunc:       testAccDeploymentImportID("spheron_deployment.test"),
+				ImportStateVerifyIgnore: []string{"compute_type", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				ResourceName:  "spheron_deployment.test",
+				ImportState:   true,
+				ImportStateId: "web=instance-1,worker",
+				ExpectError:   regexp.MustCompile(`Expected import identifier with format:\s+service_name=instance_id`),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceConfig("v2", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_deployment.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.0.tag", "v2"),
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.0.id", func(value string) error {
+						if value != webID {
+							return fmt.Errorf("expected web to be updated in place, got new instance %s", value)
+						}
+						return nil
+					}),
+					testAccCheckActiveOrder("service.0.id", func(order client.InstanceOrder) error {
+						if order.ClusterInstanceConfiguration.Tag != "v2" {
+							return fmt.Errorf("expected active tag v2, got %s", order.ClusterInstanceConfiguration.Tag)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceConfig("v2", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_deployment.test", "service.1.replicas", "2"),
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.1.id", func(value string) error {
+						if value == workerID {
+							return fmt.Errorf("expected worker to be redeployed")
+						}
+						if instance, _ := server.Instance(workerID); instance.State != "Closed" {
+							return fmt.Errorf("expected previous worker instance %s to be closed, got %s", workerID, instance.State)
+						}
+						workerID = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceConfig("v2", 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccDeploymentResource_replaceFailure(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.FailingTags = []string{"broken"}
+
+	var webID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceReplaceConfig("v1", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.0.id", func(value string) error {
+						webID = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccProviderConfig + testAccDeploymentResourceReplaceConfig("broken", 2),
+				ExpectError: regexp.MustCompile(`Service\s+web\s+was\s+not\s+deployed`),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceReplaceConfig("v1", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.0.id", func(value string) error {
+						if value != webID {
+							return fmt.Errorf("expected instance %s to be kept, got %s", webID, value)
+						}
+						if instance, _ := server.Instance(value); instance.State != "Active" {
+							return fmt.Errorf("expected instance %s to keep running, got %s", value, instance.State)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccDeploymentResourceReplaceConfig("v2", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("spheron_deployment.test", "service.0.id", func(value string) error {
+						if value == webID {
+							return fmt.Errorf("expected web to be redeployed")
+						}
+						if instance, _ := server.Instance(webID); instance.State != "Closed" {
+							return fmt.Errorf("expected previous instance %s to be closed, got %s", webID, instance.State)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccDeploymentImportID(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		var services []string
+		for i := 0; rs.Primary.Attributes[fmt.Sprintf("service.%d.id", i)] != ""; i++ {
+			services = append(services, rs.Primary.Attributes[fmt.Sprintf("service.%d.name", i)]+"="+rs.Primary.Attributes[fmt.Sprintf("service.%d.id", i)])
+		}
+
+		return strings.Join(services, ","), nil
+	}
+}
+
+func testAccDeploymentResourceReplaceConfig(tag string, replicas int) string {
+	return fmt.Sprintf(`
+resource "spheron_deployment" "test" {
+  name   = "tf_test_deployment_replace"
+  region = "any"
+
+  service {
+    name     = "web"
+    image    = "crccheck/hello-world"
+    tag      = %q
+    cpu      = 1
+    memory   = 2
+    storage  = 10
+    replicas = %d
+
+    ports = [
+      {
+        container_port = 8000
+        exposed_port   = 80
+      }
+    ]
+  }
+}
+`, tag, replicas)
+}
+
+func testAccDeploymentResourceConfig(webTag string, workerReplicas int) string {
+	return fmt.Sprintf(`
+resource "spheron_deployment" "test" {
+  name   = "tf_test_deployment"
+  region = "any"
+
+  service {
+    name    = "web"
+    image   = "crccheck/hello-world"
+    tag     = %q
+    cpu     = 1
+    memory  = 2
+    storage = 10
+
+    ports = [
+      {
+        container_port = 8000
+        exposed_port   = 80
+      }
+    ]
+
+    env = [
+      {
+        key   = "MODE"
+        value = "web"
+      }
+    ]
+
+    depends_on = ["redis"]
+  }
+
+  service {
+    name          = "worker"
+    image         = "crccheck/hello-world"
+    tag           = "latest"
+    machine_image = "Ventus Nano"
+    storage       = 10
+    replicas      = %d
+    commands      = ["worker"]
+
+    depends_on = ["redis"]
+  }
+
+  service {
+    name    = "redis"
+    image   = "redis"
+    tag     = "7"
+    cpu     = 1
+    memory  = 1
+    storage = 10
+
+    ports = [
+      {
+        container_port = 6379
+      }
+    ]
+  }
+}
+`, webTag, workerReplicas)
+}
+
+func testAccDeploymentResourceCycleConfig() string {
+	return `
+resource "spheron_deployment" "test" {
+  name   = "tf_test_deployment"
+  region = "any"
+
+  service {
+    name       = "worker"
+    image      = "crccheck/hello-world"
+    tag        = "latest"
+    cpu        = 1
+    memory     = 1
+    storage    = 10
+    depends_on = ["redis"]
+  }
+
+  service {
+    name       = "redis"
+    image      = "redis"
+    tag        = "7"
+    cpu        = 1
+    memory     = 1
+    storage    = 10
+    depends_on = ["worker"]
+  }
+}
+`
+}
+
+func TestDeploymentServiceRequiresReplace(t *testing.T) {
+	state := DeploymentServiceModel{
+		Image:    types.StringValue("redis"),
+		Tag:      types.StringValue("7"),
+		Replicas: types.Int64Value(1),
+		Storage:  types.Int64Value(10),
+		Cpu:      types.StringValue("1"),
+		Memory:   types.StringValue("1"),
+		Ports: []Port{
+			{ContainerPort: types.Int64Value(6379), ExposedPort: types.Int64Value(31234)},
+		},
+	}
+
+	plan := state
+	plan.Tag = types.StringValue("8")
+	plan.Ports = []Port{
+		{ContainerPort: types.Int64Value(6379), ExposedPort: types.Int64Unknown()},
+	}
+	if deploymentServiceRequiresReplace(plan, state) {
+		t.Error("expected tag change with unknown exposed port to be updated in place")
+	}
+
+	plan.Replicas = types.Int64Value(2)
+	if !deploymentServiceRequiresReplace(plan, state) {
+		t.Error("expected replicas change to redeploy the service")
+	}
+
+	plan = state
+	plan.Ports = []Port{
+		{ContainerPort: types.Int64Value(6380), ExposedPort: types.Int64Unknown()},
+	}
+	if !deploymentServiceRequiresReplace(plan, state) {
+		t.Error("expected container port change to redeploy the service")
+	}
+
+	plan = state
+	plan.Cpu = types.StringValue("1000m")
+	plan.Memory = types.StringValue("1024Mi")
+	if deploymentServiceRequiresReplace(plan, state) {
+		t.Error("expected cpu and memory of the same size to be kept")
+	}
+
+	plan.Memory = types.StringValue("2Gi")
+	if !deploymentServiceRequiresReplace(plan, state) {
+		t.Error("expected memory change to redeploy the service")
+	}
+}
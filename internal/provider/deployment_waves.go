@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getServiceDeploymentWaves groups the services so that every service is deployed after the services it
+// depends on. Services of the same wave keep their configuration order.
+func getServiceDeploymentWaves(services []DeploymentServiceModel) ([][]string, error) {
+	names := map[string]bool{}
+	for _, service := range services {
+		if service.Name.IsUnknown() {
+			return nil, nil
+		}
+
+		name := service.Name.ValueString()
+		if names[name] {
+			return nil, fmt.Errorf("Service %s is defined more than once.", name)
+		}
+		names[name] = true
+	}
+
+	for _, service := range services {
+		for _, dependency := range service.DependsOn {
+			if dependency == service.Name.ValueString() {
+				return nil, fmt.Errorf("Service %s depends on itself.", dependency)
+			}
+			if !names[dependency] {
+				return nil, fmt.Errorf("Service %s depends on unknown service %s.", service.Name.ValueString(), dependency)
+			}
+		}
+	}
+
+	deployed := map[string]bool{}
+	var waves [][]string
+
+	for len(deployed) < len(services) {
+		var wave []string
+		for _, service := range services {
+			name := service.Name.ValueString()
+			if deployed[name] {
+				continue
+			}
+
+			ready := true
+			for _, dependency := range service.DependsOn {
+				ready = ready && deployed[dependency]
+			}
+			if ready {
+				wave = append(wave, name)
+			}
+		}
+
+		if len(wave) == 0 {
+			var remaining []string
+			for _, service := range services {
+				if !deployed[service.Name.ValueString()] {
+					remaining = append(remaining, service.Name.ValueString())
+				}
+			}
+			return nil, fmt.Errorf("Services %s have circular dependencies.", strings.Join(remaining, ", "))
+		}
+
+		for _, name := range wave {
+			deployed[name] = true
+		}
+		waves = append(waves, wave)
+	}
+
+	return waves, nil
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGetServiceDeploymentWaves(t *testing.T) {
+	service := func(name string, dependsOn ...string) DeploymentServiceModel {
+		return DeploymentServiceModel{Name: types.StringValue(name), DependsOn: dependsOn}
+	}
+
+	testCases := map[string]struct {
+		services []DeploymentServiceModel
+		expected [][]string
+		wantErr  string
+	}{
+		"no dependencies": {
+			services: []DeploymentServiceModel{service("web"), service("redis")},
+			expected: [][]string{{"web", "redis"}},
+		},
+		"dependencies": {
+			services: []DeploymentServiceModel{service("web", "api"), service("api", "redis"), service("worker", "redis"), service("redis")},
+			expected: [][]string{{"redis"}, {"api", "worker"}, {"web"}},
+		},
+		"duplicate": {
+			services: []DeploymentServiceModel{service("web"), service("web")},
+			wantErr:  "Service web is defined more than once.",
+		},
+		"unknown dependency": {
+			services: []DeploymentServiceModel{service("web", "db")},
+			wantErr:  "Service web depends on unknown service db.",
+		},
+		"self dependency": {
+			services: []DeploymentServiceModel{service("web", "web")},
+			wantErr:  "Service web depends on itself.",
+		},
+		"cycle": {
+			services: []DeploymentServiceModel{service("redis"), service("web", "api"), service("api", "web")},
+			wantErr:  "Services web, api have circular dependencies.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			waves, err := getServiceDeploymentWaves(tc.services)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("expected %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if !reflect.DeepEqual(waves, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, waves)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"strings"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// getDomainDNSRecords returns the DNS records of the domain. The API doesn't always return them, so without
+// records a record pointing at the instance link is derived from the domain type.
+func getDomainDNSRecords(domain client.Domain) []client.DomainDNSRecord {
+	if len(domain.DNSRecords) != 0 {
+		return domain.DNSRecords
+	}
+
+	if domain.Link == "" {
+		return nil
+	}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(domain.Link, "https://"), "http://")
+	host = strings.SplitN(host, ":", 2)[0]
+
+	switch domain.Type {
+	case client.DomainTypeEns, client.DomainTypeHns:
+		return nil
+	case client.DomainTypeApex:
+		return []client.DomainDNSRecord{
+			{
+				Type:  "ALIAS",
+				Name:  domain.Name,
+				Value: host,
+			},
+		}
+	}
+
+	return []client.DomainDNSRecord{
+		{
+			Type:  "CNAME",
+			Name:  domain.Name,
+			Value: host,
+		},
+	}
+}
+
+func getDomainCertificateStatus(domain client.Domain) (types.String, types.String) {
+	if domain.Certificate == nil {
+		return types.StringValue(""), types.StringValue("")
+	}
+
+	expiresAt := ""
+	if !domain.Certificate.ExpiresAt.IsZero() {
+		expiresAt = domain.Certificate.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return types.StringValue(domain.Certificate.Status), types.StringValue(expiresAt)
+}
+
+func mapDomainDNSRecordsToValue(records []client.DomainDNSRecord) []attr.Value {
+	recordList := make([]attr.Value, 0, len(records))
+	for _, record := range records {
+		recordValues := map[string]attr.Value{
+			"type":  types.StringValue(record.Type),
+			"name":  types.StringValue(record.Name),
+			"value": types.StringValue(record.Value),
+		}
+
+		recordList = append(recordList, types.ObjectValueMust(getDNSRecordAtrTypes(), recordValues))
+	}
+	return recordList
+}
+
+func getDNSRecordAtrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"type":  types.StringType,
+		"name":  types.StringType,
+		"value": types.StringType,
+	}
+}
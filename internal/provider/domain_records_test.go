@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+)
+
+func TestGetDomainDNSRecords(t *testing.T) {
+	returned := []client.DomainDNSRecord{
+		{Type: "CNAME", Name: "app.example.com", Value: "provider.example.com"},
+		{Type: "TXT", Name: "_spheron-challenge.app.example.com", Value: "challenge"},
+	}
+
+	testCases := map[string]struct {
+		domain   client.Domain
+		expected []client.DomainDNSRecord
+	}{
+		"returned by the API": {
+			domain:   client.Domain{Name: "app.example.com", Type: client.DomainTypeSubdomain, Link: "https://other.example.com", DNSRecords: returned},
+			expected: returned,
+		},
+		"derived subdomain": {
+			domain:   client.Domain{Name: "app.example.com", Type: client.DomainTypeSubdomain, Link: "https://provider.example.com:31234"},
+			expected: []client.DomainDNSRecord{{Type: "CNAME", Name: "app.example.com", Value: "provider.example.com"}},
+		},
+		"derived apex": {
+			domain:   client.Domain{Name: "example.com", Type: client.DomainTypeApex, Link: "provider.example.com"},
+			expected: []client.DomainDNSRecord{{Type: "ALIAS", Name: "example.com", Value: "provider.example.com"}},
+		},
+		"ens": {
+			domain: client.Domain{Name: "app.eth", Type: client.DomainTypeEns, Link: "https://provider.example.com"},
+		},
+		"no link": {
+			domain: client.Domain{Name: "app.example.com", Type: client.DomainTypeDomain},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if records := getDomainDNSRecords(tc.domain); !reflect.DeepEqual(records, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, records)
+			}
+		})
+	}
+}
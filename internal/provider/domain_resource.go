@@ -3,12 +3,19 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"terraform-provider-spheron/internal/client"
 
@@ -18,18 +25,29 @@ import (
 
 var _ resource.Resource = &DomainResource{}
 var _ resource.ResourceWithImportState = &DomainResource{}
+var _ resource.ResourceWithModifyPlan = &DomainResource{}
+var _ resource.ResourceWithValidateConfig = &DomainResource{}
+
+const domainVerificationPollInterval = 10 * time.Second
 
 type DomainResource struct {
 	client *client.SpheronApi
+	token  *tokenCheck
 }
 
 type DomainResourceModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	Verified     types.Bool   `tfsdk:"verified"`
-	InstancePort types.Int64  `tfsdk:"instance_port"`
-	Type         types.String `tfsdk:"type"`
-	InstanceID   types.String `tfsdk:"instance_id"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Verified             types.Bool   `tfsdk:"verified"`
+	InstancePort         types.Int64  `tfsdk:"instance_port"`
+	Type                 types.String `tfsdk:"type"`
+	InstanceID           types.String `tfsdk:"instance_id"`
+	Link                 types.String `tfsdk:"link"`
+	WaitForVerification  types.Bool   `tfsdk:"wait_for_verification"`
+	VerificationTimeout  types.Int64  `tfsdk:"verification_timeout"`
+	DNSRecords           types.List   `tfsdk:"dns_records"`
+	CertificateStatus    types.String `tfsdk:"certificate_status"`
+	CertificateExpiresAt types.String `tfsdk:"certificate_expires_at"`
 }
 
 func NewDomainResource() resource.Resource {
@@ -64,11 +82,21 @@ func (r *DomainResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "Type of the domain. Available options are domain and subdomain.",
+				MarkdownDescription: "Type of the domain. Available options are domain, subdomain, wildcard, apex, ens and hns.",
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(client.DomainTypeDomain),
+						string(client.DomainTypeSubdomain),
+						string(client.DomainTypeWildcard),
+						string(client.DomainTypeApex),
+						string(client.DomainTypeEns),
+						string(client.DomainTypeHns),
+					),
+				},
 			},
 			"instance_id": schema.StringAttribute{
 				MarkdownDescription: "The id of an instance to which to attach the domain.",
@@ -77,30 +105,114 @@ func (r *DomainResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"link": schema.StringAttribute{
+				MarkdownDescription: "Instance URL the domain points to. The domain is re-linked in place when the instance URL for instance_port changes.",
+				Computed:            true,
+			},
+			"wait_for_verification": schema.BoolAttribute{
+				MarkdownDescription: "Wait for the domain to be verified before finishing the apply. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"verification_timeout": schema.Int64Attribute{
+				MarkdownDescription: "Time in minutes to wait for the domain verification when wait_for_verification is set. Defaults to 10.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(10),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"dns_records": schema.ListNestedAttribute{
+				MarkdownDescription: "DNS records that have to be created at the domain DNS provider for the domain to be verified.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Record type, e.g. CNAME, A or TXT.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Record name.",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "Record value.",
+							Computed:            true,
+						},
+					},
+				},
+				Computed: true,
+			},
+			"certificate_status": schema.StringAttribute{
+				MarkdownDescription: "Status of the TLS certificate issued for the domain.",
+				Computed:            true,
+			},
+			"certificate_expires_at": schema.StringAttribute{
+				MarkdownDescription: "Expiration time of the TLS certificate issued for the domain, in RFC3339 format.",
+				Computed:            true,
+			},
 		},
 	}
 }
 
+func (r *DomainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config DomainResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.IsUnknown() || config.Name.IsUnknown() {
+		return
+	}
+
+	isWildcardName := strings.HasPrefix(config.Name.ValueString(), "*.")
+
+	if config.Type.ValueString() == string(client.DomainTypeWildcard) && !isWildcardName {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid wildcard domain name",
+			fmt.Sprintf("Wildcard domain name must start with \"*.\", got: %s", config.Name.ValueString()),
+		)
+	}
+
+	if config.Type.ValueString() != string(client.DomainTypeWildcard) && isWildcardName {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid domain type",
+			fmt.Sprintf("Domain %s is a wildcard domain. Use type wildcard for it.", config.Name.ValueString()),
+		)
+	}
+}
+
 func (r *DomainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SpheronApi)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.token = data.token
 }
 
 func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan DomainResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -109,11 +221,11 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	if !isValidDomainType(plan.Type.ValueString()) {
-		resp.Diagnostics.AddError("DomainType not supported.", "DomainType not supported. Supported domain types are: doain and subdomain.")
+		resp.Diagnostics.AddError("DomainType not supported.", "DomainType not supported. Supported domain types are: domain, subdomain, wildcard, apex, ens and hns.")
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(plan.InstanceID.ValueString())
+	instance, err := r.client.GetClusterInstance(ctx, plan.InstanceID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -123,7 +235,7 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -149,7 +261,7 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 		Link: url,
 	}
 
-	domain, err := r.client.AddClusterInstanceDomain(plan.InstanceID.ValueString(), domainRequest)
+	domain, err := r.client.AddClusterInstanceDomain(ctx, plan.InstanceID.ValueString(), domainRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create domain",
@@ -159,7 +271,25 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	plan.ID = types.StringValue(domain.ID)
+	plan.Link = types.StringValue(domain.Link)
 	plan.Verified = types.BoolValue(domain.Verified)
+	plan.DNSRecords = types.ListValueMust(types.ObjectType{AttrTypes: getDNSRecordAtrTypes()}, mapDomainDNSRecordsToValue(getDomainDNSRecords(domain)))
+	plan.CertificateStatus, plan.CertificateExpiresAt = getDomainCertificateStatus(domain)
+
+	if plan.WaitForVerification.ValueBool() && !domain.Verified {
+		domain, err = r.waitForDomainVerification(ctx, plan.InstanceID.ValueString(), domain.ID, time.Duration(plan.VerificationTimeout.ValueInt64())*time.Minute)
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Domain verification failed",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.Verified = types.BoolValue(domain.Verified)
+		plan.CertificateStatus, plan.CertificateExpiresAt = getDomainCertificateStatus(domain)
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -169,6 +299,16 @@ func (r *DomainResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		tflog.Debug(ctx, "Spheron client is not configured yet, keeping prior state")
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state DomainResourceModel
 	tflog.Debug(ctx, "Preparing to read item resource")
 
@@ -186,7 +326,7 @@ func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	domains, err := r.client.GetClusterInstanceDomains(state.InstanceID.ValueString())
+	domains, err := r.client.GetClusterInstanceDomains(ctx, state.InstanceID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Coudn't fetch instance domains for provided instance id.",
@@ -195,7 +335,7 @@ func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(state.InstanceID.ValueString())
+	instance, err := r.client.GetClusterInstance(ctx, state.InstanceID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Coudn't fetch instance for specified domain.",
@@ -210,7 +350,7 @@ func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 	if err != nil {
 		resp.State.RemoveResource(ctx)
 		resp.Diagnostics.AddWarning("Instance domain is attached to doesn't have provisioned deployments.",
@@ -220,25 +360,53 @@ func (r *DomainResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	domain, err := findDomainByID(domains, state.ID.ValueString())
+	if err != nil {
+		resp.State.RemoveResource(ctx)
+		resp.Diagnostics.AddWarning("Domain not found.",
+			err.Error(),
+		)
+		return
+	}
 
 	containerPort, err := getPortFromDeploymentURL(order, domain.Link)
-	if err != nil {
+	if err == nil {
+		state.InstancePort = types.Int64Value(int64(containerPort))
+	} else if state.InstancePort.IsNull() {
 		resp.State.RemoveResource(ctx)
 		resp.Diagnostics.AddWarning("Instance doesn't have provisioned deployments.",
 			err.Error(),
 		)
 		return
+	} else {
+		resp.Diagnostics.AddWarning("Domain link is outdated.",
+			fmt.Sprintf("Domain %s points to %s which is no longer served by the instance. Applying will re-link the domain to the current instance URL.", domain.Name, domain.Link),
+		)
 	}
 
-	state.InstancePort = types.Int64Value(int64(containerPort))
+	state.Link = types.StringValue(domain.Link)
 	state.Name = types.StringValue(domain.Name)
 	state.Verified = types.BoolValue(domain.Verified)
 	state.Type = types.StringValue(string(domain.Type))
+	state.DNSRecords = types.ListValueMust(types.ObjectType{AttrTypes: getDNSRecordAtrTypes()}, mapDomainDNSRecordsToValue(getDomainDNSRecords(domain)))
+	state.CertificateStatus, state.CertificateExpiresAt = getDomainCertificateStatus(domain)
+
+	if state.WaitForVerification.IsNull() {
+		state.WaitForVerification = types.BoolValue(false)
+	}
+
+	if state.VerificationTimeout.IsNull() {
+		state.VerificationTimeout = types.Int64Value(10)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan DomainResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
@@ -248,11 +416,11 @@ func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	if !isValidDomainType(plan.Type.ValueString()) {
-		resp.Diagnostics.AddError("DomainType not supported.", "DomainType not supported. Supported domain types are: doain and subdomain.")
+		resp.Diagnostics.AddError("DomainType not supported.", "DomainType not supported. Supported domain types are: domain, subdomain, wildcard, apex, ens and hns.")
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(plan.InstanceID.ValueString())
+	instance, err := r.client.GetClusterInstance(ctx, plan.InstanceID.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -262,7 +430,7 @@ func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -288,7 +456,7 @@ func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest,
 		Link: url,
 	}
 
-	domain, err := r.client.UpdateClusterInstanceDomain(plan.InstanceID.ValueString(), plan.ID.ValueString(), domainRequest)
+	domain, err := r.client.UpdateClusterInstanceDomain(ctx, plan.InstanceID.ValueString(), plan.ID.ValueString(), domainRequest)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create domain",
@@ -297,7 +465,25 @@ func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	plan.Link = types.StringValue(domain.Link)
 	plan.Verified = types.BoolValue(domain.Verified)
+	plan.DNSRecords = types.ListValueMust(types.ObjectType{AttrTypes: getDNSRecordAtrTypes()}, mapDomainDNSRecordsToValue(getDomainDNSRecords(domain)))
+	plan.CertificateStatus, plan.CertificateExpiresAt = getDomainCertificateStatus(domain)
+
+	if plan.WaitForVerification.ValueBool() && !domain.Verified {
+		domain, err = r.waitForDomainVerification(ctx, plan.InstanceID.ValueString(), plan.ID.ValueString(), time.Duration(plan.VerificationTimeout.ValueInt64())*time.Minute)
+		if err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.AddError(
+				"Domain verification failed",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.Verified = types.BoolValue(domain.Verified)
+		plan.CertificateStatus, plan.CertificateExpiresAt = getDomainCertificateStatus(domain)
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -307,7 +493,69 @@ func (r *DomainResource) Update(ctx context.Context, req resource.UpdateRequest,
 	tflog.Debug(ctx, "Updated item resource", map[string]any{"success": true})
 }
 
+func (r *DomainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state DomainResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.InstanceID.IsUnknown() || plan.InstancePort.IsUnknown() || r.client == nil {
+		return
+	}
+
+	instance, err := r.client.GetClusterInstance(ctx, plan.InstanceID.ValueString())
+	if err != nil {
+		r.warnLinkNotChecked(ctx, state, err, resp)
+		return
+	}
+	if instance.ActiveOrder == "" {
+		return
+	}
+
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+	if err != nil {
+		r.warnLinkNotChecked(ctx, state, err, resp)
+		return
+	}
+
+	url := getInstanceDeploymentURL(order, int(plan.InstancePort.ValueInt64()))
+	if url == "" || url == state.Link.ValueString() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("link"), state.Link)...)
+		return
+	}
+
+	tflog.Debug(ctx, "Domain link changed, planning re-link", map[string]any{"from": state.Link.ValueString(), "to": url})
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("link"), url)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("verified"), types.BoolUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("dns_records"), types.ListUnknown(types.ObjectType{AttrTypes: getDNSRecordAtrTypes()}))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_status"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("certificate_expires_at"), types.StringUnknown())...)
+}
+
+// warnLinkNotChecked keeps the current link when the instance URL can't be fetched during plan.
+func (r *DomainResource) warnLinkNotChecked(ctx context.Context, state DomainResourceModel, err error, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.AddAttributeWarning(
+		path.Root("link"),
+		"Unable to check domain link.",
+		fmt.Sprintf("The instance URL couldn't be fetched, so the domain is not re-linked if the URL changed: %s", err.Error()),
+	)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("link"), state.Link)...)
+}
+
 func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Preparing to delete item resource")
 	var state DomainResourceModel
 
@@ -318,7 +566,7 @@ func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.DeleteClusterInstanceDomain(state.InstanceID.ValueString(), state.ID.ValueString())
+	err := r.client.DeleteClusterInstanceDomain(ctx, state.InstanceID.ValueString(), state.ID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to destroy Instance",
@@ -331,5 +579,48 @@ func (r *DomainResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *DomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	idParts := strings.Split(req.ID, "/")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected import identifier",
+			fmt.Sprintf("Expected import identifier with format: instance_id/domain_id. Got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("instance_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+func (r *DomainResource) waitForDomainVerification(ctx context.Context, instanceID string, domainID string, timeout time.Duration) (client.Domain, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		domains, err := r.client.GetClusterInstanceDomains(ctx, instanceID)
+		if err != nil {
+			return client.Domain{}, err
+		}
+
+		domain, err := findDomainByID(domains, domainID)
+		if err != nil {
+			return client.Domain{}, err
+		}
+
+		if domain.Verified {
+			return domain, nil
+		}
+
+		if time.Now().After(deadline) {
+			return domain, fmt.Errorf("Domain %s was not verified within %s. Make sure the DNS records are created.", domain.Name, timeout)
+		}
+
+		tflog.Debug(ctx, "Waiting for domain verification", map[string]any{"domain": domain.Name})
+
+		select {
+		case <-ctx.Done():
+			return domain, ctx.Err()
+		case <-time.After(domainVerificationPollInterval):
+		}
+	}
 }
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-spheron/internal/client/fake"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDomainResource(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.AutoVerifyDomains = true
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccDomainResourceConfig(80, "app.example.com", "subdomain"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("spheron_domain.test", "id"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "verified", "true"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "instance_port", "8000"),
+					resource.TestCheckResourceAttrPair("spheron_domain.test", "instance_id", "spheron_instance.test", "id"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "dns_records.#", "2"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "dns_records.0.type", "CNAME"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "dns_records.0.name", "app.example.com"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "dns_records.1.type", "TXT"),
+					resource.TestCheckResourceAttr("spheron_domain.test", "dns_records.1.name", "_spheron-challenge.app.example.com"),
+				),
+			},
+			{
+				ResourceName:      "spheron_domain.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccDomainImportID("spheron_domain.test"),
+			},
+			{
+				ResourceName:  "spheron_domain.test",
+				ImportState:   true,
+				ImportStateId: "only-domain-id",
+				ExpectError:   regexp.MustCompile("Expected import identifier with format: instance_id/domain_id"),
+			},
+		},
+	})
+}
+
+func TestAccDomainResource_relink(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	var instanceID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccDomainResourceConfig(0, "app.example.com", "subdomain"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+						instanceID = value
+						return nil
+					}),
+					resource.TestMatchResourceAttr("spheron_domain.test", "link", regexp.MustCompile("^"+regexp.QuoteMeta(fake.DefaultProviderHost)+":")),
+				),
+			},
+			{
+				PreConfig: func() {
+					server.MoveInstance(instanceID, "moved.fake.spheron.network")
+				},
+				Config: testAccProviderConfig + testAccDomainResourceConfig(0, "app.example.com", "subdomain"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_domain.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("spheron_domain.test", "link", regexp.MustCompile(`^moved\.fake\.spheron\.network:`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDomainResource_waitForVerification(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccDomainResourceConfig(80, "app.example.com", "subdomain") + `
+resource "spheron_domain" "wait" {
+  name                  = "wait.example.com"
+  type                  = "subdomain"
+  instance_port         = spheron_instance.test.ports[0].container_port
+  instance_id           = spheron_instance.test.id
+  wait_for_verification = true
+  verification_timeout  = 1
+}
+`,
+				ExpectError: regexp.MustCompile("was not verified within"),
+			},
+		},
+	})
+}
+
+func TestAccDomainResource_wildcardName(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccDomainResourceConfig(80, "app.example.com", "wildcard"),
+				ExpectError: regexp.MustCompile("Wildcard domain name must start with"),
+			},
+		},
+	})
+}
+
+func testAccDomainResourceConfig(exposedPort int, name string, domainType string) string {
+	exposed := ""
+	if exposedPort != 0 {
+		exposed = fmt.Sprintf("exposed_port = %d", exposedPort)
+	}
+
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_domain"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+      %[1]s
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 1
+  replicas     = 1
+  compute_type = "SPOT"
+}
+
+resource "spheron_domain" "test" {
+  name          = %[2]q
+  type          = %[3]q
+  instance_port = spheron_instance.test.ports[0].container_port
+  instance_id   = spheron_instance.test.id
+}
+`, exposed, name, domainType)
+}
+
+func testAccDomainImportID(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		return rs.Primary.Attributes["instance_id"] + "/" + rs.Primary.ID, nil
+	}
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func mapClientRegionsToGpuModels(regions []client.Region, region string) []GpuModel {
+	mapped := []GpuModel{}
+	for _, r := range regions {
+		if region != "" && region != "any" && r.Name != region {
+			continue
+		}
+
+		for _, gpu := range r.Gpus {
+			vram, _ := strconv.Atoi(RemoveGiSuffix(gpu.Vram))
+			mapped = append(mapped, GpuModel{
+				Region:       types.StringValue(r.Name),
+				Vendor:       types.StringValue(gpu.Vendor),
+				Model:        types.StringValue(gpu.Model),
+				Vram:         types.Int64Value(int64(vram)),
+				Available:    types.Int64Value(int64(gpu.Available)),
+				PricePerHour: types.Float64Value(gpu.PricePerHour),
+			})
+		}
+	}
+
+	return mapped
+}
+
+// getGpuSpecs returns the GPU specs of the gpu attribute, or false when they are not known yet or can't be read.
+func getGpuSpecs(ctx context.Context, gpu types.Object) (*client.GpuSpecs, bool, diag.Diagnostics) {
+	if gpu.IsNull() {
+		return nil, true, nil
+	}
+	if gpu.IsUnknown() {
+		return nil, false, nil
+	}
+
+	var model Gpu
+	diags := gpu.As(ctx, &model, basetypes.ObjectAsOptions{})
+	if diags.HasError() || model.Count.IsUnknown() || model.Model.IsUnknown() {
+		return nil, false, diags
+	}
+
+	// Vendor and VRAM are read from the catalog when they are not configured.
+	specs := &client.GpuSpecs{
+		Count:  int(model.Count.ValueInt64()),
+		Vendor: model.Vendor.ValueString(),
+		Model:  model.Model.ValueString(),
+	}
+	if !model.Vram.IsUnknown() && !model.Vram.IsNull() {
+		specs.Vram = fmt.Sprintf("%dGi", model.Vram.ValueInt64())
+	}
+
+	return specs, true, diags
+}
+
+func mapClientGpuToGpu(gpu *client.GpuSpecs) types.Object {
+	if gpu == nil || gpu.Count == 0 {
+		return types.ObjectNull(gpuAttrTypes)
+	}
+
+	vram, _ := strconv.Atoi(RemoveGiSuffix(gpu.Vram))
+
+	return types.ObjectValueMust(gpuAttrTypes, map[string]attr.Value{
+		"count":  types.Int64Value(int64(gpu.Count)),
+		"vendor": types.StringValue(gpu.Vendor),
+		"model":  types.StringValue(gpu.Model),
+		"vram":   types.Int64Value(int64(vram)),
+	})
+}
+
+// gpuCatalogValue keeps the vendor or VRAM read from the GPU catalog while the GPU model and count don't change.
+type gpuCatalogValue struct{}
+
+func (m gpuCatalogValue) Description(ctx context.Context) string {
+	return "Uses the prior value while the GPU model and count are unchanged."
+}
+
+func (m gpuCatalogValue) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m gpuCatalogValue) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.PlanValue.IsUnknown() && !req.StateValue.IsNull() && isGpuUnchanged(ctx, req.Plan, req.State, req.Path.ParentPath()) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+func (m gpuCatalogValue) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.PlanValue.IsUnknown() && !req.StateValue.IsNull() && isGpuUnchanged(ctx, req.Plan, req.State, req.Path.ParentPath()) {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+func isGpuUnchanged(ctx context.Context, plan tfsdk.Plan, state tfsdk.State, gpuPath path.Path) bool {
+	var planModel, stateModel types.String
+	var planCount, stateCount types.Int64
+
+	diags := plan.GetAttribute(ctx, gpuPath.AtName("model"), &planModel)
+	diags.Append(state.GetAttribute(ctx, gpuPath.AtName("model"), &stateModel)...)
+	diags.Append(plan.GetAttribute(ctx, gpuPath.AtName("count"), &planCount)...)
+	diags.Append(state.GetAttribute(ctx, gpuPath.AtName("count"), &stateCount)...)
+
+	return !diags.HasError() && planModel.Equal(stateModel) && planCount.Equal(stateCount)
+}
+
+func requiresReplaceIfGpuAddedOrRemoved(ctx context.Context, req planmodifier.ObjectRequest, resp *objectplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.PlanValue.IsNull() != req.StateValue.IsNull()
+}
+
+// getAvailableGpus checks the GPU against the catalog and returns how many matching GPUs the best region has available.
+func getAvailableGpus(regions []client.Region, region string, gpu client.GpuSpecs) (int, error) {
+	models := []string{}
+	matching := map[string][]client.RegionGpu{}
+
+	for _, r := range regions {
+		if region != "any" && r.Name != region {
+			continue
+		}
+
+		for _, g := range r.Gpus {
+			if !containsString(models, g.Model) {
+				models = append(models, g.Model)
+			}
+			if g.Model == gpu.Model {
+				matching[r.Name] = append(matching[r.Name], g)
+			}
+		}
+	}
+
+	if len(models) == 0 {
+		return 0, fmt.Errorf("No GPU models are available in region %q.", region)
+	}
+	if len(matching) == 0 {
+		sort.Strings(models)
+		return 0, validateCatalogName("GPU model", gpu.Model, models)
+	}
+
+	vendors := []string{}
+	vrams := []string{}
+	available := 0
+
+	for _, gpus := range matching {
+		regionAvailable := 0
+		for _, g := range gpus {
+			if !containsString(vendors, g.Vendor) {
+				vendors = append(vendors, g.Vendor)
+			}
+			if !containsString(vrams, RemoveGiSuffix(g.Vram)) {
+				vrams = append(vrams, RemoveGiSuffix(g.Vram))
+			}
+			if (gpu.Vendor == "" || g.Vendor == gpu.Vendor) && (gpu.Vram == "" || g.Vram == gpu.Vram) {
+				regionAvailable += g.Available
+			}
+		}
+
+		if regionAvailable > available {
+			available = regionAvailable
+		}
+	}
+
+	if gpu.Vendor != "" && !containsString(vendors, gpu.Vendor) {
+		return 0, fmt.Errorf("GPU model %q is made by %s.", gpu.Model, strings.Join(vendors, ", "))
+	}
+	if gpu.Vram != "" && !containsString(vrams, RemoveGiSuffix(gpu.Vram)) {
+		sort.Slice(vrams, func(i, j int) bool {
+			a, _ := strconv.Atoi(vrams[i])
+			b, _ := strconv.Atoi(vrams[j])
+			return a < b
+		})
+		return 0, fmt.Errorf("GPU model %q is available with %s GB of VRAM.", gpu.Model, strings.Join(vrams, ", "))
+	}
+
+	return available, nil
+}
+
+func validateInstanceGpu(ctx context.Context, api *client.SpheronApi, region types.String, gpu types.Object, replicas types.Int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if region.IsUnknown() || replicas.IsUnknown() {
+		return diags
+	}
+
+	specs, ok, specDiags := getGpuSpecs(ctx, gpu)
+	diags.Append(specDiags...)
+	if !ok || specs == nil {
+		return diags
+	}
+
+	regions, err := api.GetRegions(ctx)
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("gpu"), "Unable to validate GPU.", err.Error())
+		return diags
+	}
+
+	available, err := getAvailableGpus(regions, region.ValueString(), *specs)
+	if err != nil {
+		diags.AddAttributeError(path.Root("gpu"), "Invalid GPU.", err.Error())
+		return diags
+	}
+
+	requested := specs.Count * int(replicas.ValueInt64())
+	if available < requested {
+		diags.AddAttributeWarning(
+			path.Root("gpu"),
+			"Requested GPUs may not be available in region.",
+			fmt.Sprintf("%d %s GPUs were requested, but at most %d are available in a single region.", requested, specs.Model, available),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &GpuModelsDataSource{}
+
+func NewGpuModelsDataSource() datasource.DataSource {
+	return &GpuModelsDataSource{}
+}
+
+type GpuModelsDataSource struct {
+	client *client.SpheronApi
+	token  *tokenCheck
+}
+
+type GpuModelsDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Region    types.String `tfsdk:"region"`
+	GpuModels []GpuModel   `tfsdk:"gpu_models"`
+}
+
+type GpuModel struct {
+	Region       types.String  `tfsdk:"region"`
+	Vendor       types.String  `tfsdk:"vendor"`
+	Model        types.String  `tfsdk:"model"`
+	Vram         types.Int64   `tfsdk:"vram"`
+	Available    types.Int64   `tfsdk:"available"`
+	PricePerHour types.Float64 `tfsdk:"price_per_hour"`
+}
+
+func (d *GpuModelsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gpu_models"
+}
+
+func (d *GpuModelsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "GPU models data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier.",
+				Computed:            true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Region for which to list GPU models. Lists GPU models of all regions when not set.",
+				Optional:            true,
+			},
+			"gpu_models": schema.ListNestedAttribute{
+				MarkdownDescription: "GPU models available for deploying instances.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"region": schema.StringAttribute{
+							MarkdownDescription: "Region in which the GPU model is available.",
+							Computed:            true,
+						},
+						"vendor": schema.StringAttribute{
+							MarkdownDescription: "GPU vendor.",
+							Computed:            true,
+						},
+						"model": schema.StringAttribute{
+							MarkdownDescription: "GPU model.",
+							Computed:            true,
+						},
+						"vram": schema.Int64Attribute{
+							MarkdownDescription: "GPU memory in GB.",
+							Computed:            true,
+						},
+						"available": schema.Int64Attribute{
+							MarkdownDescription: "Number of GPUs currently available in the region.",
+							Computed:            true,
+						},
+						"price_per_hour": schema.Float64Attribute{
+							MarkdownDescription: "Price in USD per GPU per hour.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *GpuModelsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare Spheron API client.")
+		return
+	}
+	d.client = data.client
+	d.token = data.token
+}
+
+func (d *GpuModelsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read GPU models data source.")
+
+	resp.Diagnostics.Append(d.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state GpuModelsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regions, err := d.client.GetRegions(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get available regions.",
+			err.Error(),
+		)
+		return
+	}
+
+	region := state.Region.ValueString()
+	if region != "" {
+		if err := validateRegion(regions, region); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("region"), "Invalid region.", err.Error())
+			return
+		}
+	}
+
+	state.ID = types.StringValue("gpu_models")
+	state.GpuModels = mapClientRegionsToGpuModels(regions, region)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading GPU models data source", map[string]any{"success": true})
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGpuModelsDataSource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+data "spheron_gpu_models" "test" {
+  region = "us-wets"
+}
+`,
+				ExpectError: regexp.MustCompile(`Region "us-wets" is not available. Did you mean "us-west"\?`),
+			},
+			{
+				Config: testAccProviderConfig + `
+data "spheron_gpu_models" "all" {}
+
+data "spheron_gpu_models" "us_west" {
+  region = "us-west"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.#", "5"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.region", "us-east"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.vendor", "nvidia"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.model", "a100"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.vram", "40"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.available", "8"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.all", "gpu_models.0.price_per_hour", "1.1"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.us_west", "gpu_models.#", "2"),
+					resource.TestCheckResourceAttr("data.spheron_gpu_models.us_west", "gpu_models.1.model", "mi300x"),
+				),
+			},
+		},
+	})
+}
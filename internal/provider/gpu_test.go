@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGetGpuSpecs(t *testing.T) {
+	ctx := context.Background()
+
+	if specs, ok, diags := getGpuSpecs(ctx, types.ObjectNull(gpuAttrTypes)); !ok || specs != nil || diags.HasError() {
+		t.Errorf("expected no gpu specs, got %+v (%v, %v)", specs, ok, diags)
+	}
+
+	if _, ok, diags := getGpuSpecs(ctx, types.ObjectUnknown(gpuAttrTypes)); ok || diags.HasError() {
+		t.Errorf("expected unknown gpu to be skipped, got %v, %v", ok, diags)
+	}
+
+	gpu := types.ObjectValueMust(gpuAttrTypes, map[string]attr.Value{
+		"count":  types.Int64Value(1),
+		"vendor": types.StringNull(),
+		"model":  types.StringValue("a100"),
+		"vram":   types.Int64Null(),
+	})
+	specs, ok, diags := getGpuSpecs(ctx, gpu)
+	if expected := (&client.GpuSpecs{Count: 1, Model: "a100"}); !ok || diags.HasError() || !reflect.DeepEqual(specs, expected) {
+		t.Errorf("expected %+v, got %+v (%v, %v)", expected, specs, ok, diags)
+	}
+
+	invalid := types.ObjectValueMust(map[string]attr.Type{"count": types.Int64Type}, map[string]attr.Value{
+		"count": types.Int64Value(1),
+	})
+	if _, ok, diags := getGpuSpecs(ctx, invalid); ok || !diags.HasError() {
+		t.Errorf("expected conversion error, got %v, %v", ok, diags)
+	}
+}
+
+func TestGetAvailableGpus(t *testing.T) {
+	regions := []client.Region{
+		{Name: "us-east", Gpus: []client.RegionGpu{
+			{Vendor: "nvidia", Model: "a100", Vram: "40Gi", Available: 8},
+			{Vendor: "nvidia", Model: "a100", Vram: "80Gi", Available: 2},
+		}},
+		{Name: "us-west", Gpus: []client.RegionGpu{
+			{Vendor: "nvidia", Model: "a100", Vram: "80Gi", Available: 4},
+			{Vendor: "nvidia", Model: "rtx4090", Vram: "24Gi", Available: 4},
+		}},
+		{Name: "eu-west"},
+	}
+
+	testCases := []struct {
+		region        string
+		gpu           client.GpuSpecs
+		wantAvailable int
+		wantErr       string
+	}{
+		{region: "us-east", gpu: client.GpuSpecs{Model: "a100"}, wantAvailable: 10},
+		{region: "us-east", gpu: client.GpuSpecs{Model: "a100", Vram: "80Gi"}, wantAvailable: 2},
+		{region: "any", gpu: client.GpuSpecs{Model: "a100", Vram: "80Gi"}, wantAvailable: 4},
+		{region: "us-west", gpu: client.GpuSpecs{Model: "rtx4090", Vendor: "nvidia"}, wantAvailable: 4},
+		{region: "us-east", gpu: client.GpuSpecs{Model: "rtx4090"}, wantErr: `GPU model "rtx4090" is not available. Available values are: a100`},
+		{region: "any", gpu: client.GpuSpecs{Model: "a10"}, wantErr: `GPU model "a10" is not available. Did you mean "a100"? Available values are: a100, rtx4090`},
+		{region: "us-east", gpu: client.GpuSpecs{Model: "a100", Vendor: "amd"}, wantErr: `GPU model "a100" is made by nvidia.`},
+		{region: "us-east", gpu: client.GpuSpecs{Model: "a100", Vram: "24Gi"}, wantErr: `GPU model "a100" is available with 40, 80 GB of VRAM.`},
+		{region: "eu-west", gpu: client.GpuSpecs{Model: "a100"}, wantErr: `No GPU models are available in region "eu-west".`},
+	}
+
+	for _, tc := range testCases {
+		available, err := getAvailableGpus(regions, tc.region, tc.gpu)
+		if tc.wantErr == "" && (err != nil || available != tc.wantAvailable) {
+			t.Errorf("getAvailableGpus(%q, %+v): expected %d, got %d, %v", tc.region, tc.gpu, tc.wantAvailable, available, err)
+		}
+		if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("getAvailableGpus(%q, %+v): expected %q, got %v", tc.region, tc.gpu, tc.wantErr, err)
+		}
+	}
+}
+
+func TestMapClientGpuToGpu(t *testing.T) {
+	if gpu := mapClientGpuToGpu(nil); !gpu.IsNull() {
+		t.Errorf("expected null gpu, got %s", gpu)
+	}
+
+	expected := types.ObjectValueMust(gpuAttrTypes, map[string]attr.Value{
+		"count":  types.Int64Value(1),
+		"vendor": types.StringValue("nvidia"),
+		"model":  types.StringValue("rtx4090"),
+		"vram":   types.Int64Value(24),
+	})
+	if gpu := mapClientGpuToGpu(&client.GpuSpecs{Count: 1, Vendor: "nvidia", Model: "rtx4090", Vram: "24Gi"}); !gpu.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, gpu)
+	}
+}
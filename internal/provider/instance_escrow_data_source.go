@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &InstanceEscrowDataSource{}
+
+func NewInstanceEscrowDataSource() datasource.DataSource {
+	return &InstanceEscrowDataSource{}
+}
+
+type InstanceEscrowDataSource struct {
+	client *client.SpheronApi
+	token  *tokenCheck
+}
+
+type InstanceEscrowDataSourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	InstanceID     types.String `tfsdk:"instance_id"`
+	State          types.String `tfsdk:"state"`
+	RetrievableAkt types.Int64  `tfsdk:"retrievable_akt"`
+	WithdrawnAkt   types.Int64  `tfsdk:"withdrawn_akt"`
+}
+
+func (d *InstanceEscrowDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_escrow"
+}
+
+func (d *InstanceEscrowDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Instance escrow data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier.",
+				Computed:            true,
+			},
+			"instance_id": schema.StringAttribute{
+				MarkdownDescription: "Id of the instance.",
+				Required:            true,
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "Instance state.",
+				Computed:            true,
+			},
+			"retrievable_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT left in the instance escrow that can still be retrieved.",
+				Computed:            true,
+			},
+			"withdrawn_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT already withdrawn from the instance escrow.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *InstanceEscrowDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare Spheron API client.")
+		return
+	}
+	d.client = data.client
+	d.token = data.token
+}
+
+func (d *InstanceEscrowDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read instance escrow data source.")
+
+	resp.Diagnostics.Append(d.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state InstanceEscrowDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err := d.client.GetClusterInstance(ctx, state.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Coudnt fetch instance by provided id.",
+			err.Error(),
+		)
+		return
+	}
+
+	state.ID = types.StringValue(instance.ID)
+	state.State = types.StringValue(instance.State)
+	state.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	state.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading instance escrow data source", map[string]any{"success": true})
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccInstanceEscrowDataSource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceConfig("latest") + `
+data "spheron_instance_escrow" "test" {
+  instance_id = spheron_instance.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.spheron_instance_escrow.test", "id", "spheron_instance.test", "id"),
+					resource.TestCheckResourceAttr("data.spheron_instance_escrow.test", "state", "Active"),
+					resource.TestCheckResourceAttr("data.spheron_instance_escrow.test", "retrievable_akt", "5000000"),
+					resource.TestCheckResourceAttr("data.spheron_instance_escrow.test", "withdrawn_akt", "0"),
+				),
+			},
+		},
+	})
+}
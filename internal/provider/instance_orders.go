@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func validateRollbackOrder(ctx context.Context, order types.String, stateOrder types.String, stateOrders types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !isPlannedChange(order, stateOrder) {
+		return diags
+	}
+
+	if stateOrders.IsNull() || stateOrders.IsUnknown() {
+		diags.AddAttributeError(path.Root("rollback_to_order"), "Invalid rollback order.", "Rollback order can only be set on an existing instance.")
+		return diags
+	}
+
+	var orders []InstanceOrder
+	diags.Append(stateOrders.ElementsAs(ctx, &orders, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	ids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.Id.ValueString() == order.ValueString() {
+			return diags
+		}
+		ids = append(ids, o.Id.ValueString())
+	}
+
+	diags.AddAttributeError(
+		path.Root("rollback_to_order"),
+		"Invalid rollback order.",
+		fmt.Sprintf("Order %s is not part of the instance history. Available orders are: %s.", order.ValueString(), strings.Join(ids, ", ")),
+	)
+	return diags
+}
+
+func mapClientOrderToOrder(order client.InstanceOrder) InstanceOrder {
+	result := InstanceOrder{
+		Id:        types.StringValue(order.ID),
+		Status:    types.StringValue(order.Status),
+		Tag:       types.StringNull(),
+		CreatedAt: types.StringNull(),
+	}
+
+	if order.ClusterInstanceConfiguration != nil {
+		result.Tag = types.StringValue(order.ClusterInstanceConfiguration.Tag)
+	}
+
+	if !order.CreatedAt.IsZero() {
+		result.CreatedAt = types.StringValue(order.CreatedAt.UTC().Format(time.RFC3339))
+	}
+
+	return result
+}
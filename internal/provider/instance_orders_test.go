@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestMapClientOrderToOrder(t *testing.T) {
+	createdAt := time.Date(2023, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	got := mapClientOrderToOrder(client.InstanceOrder{
+		ID:                           "order-1",
+		Status:                       "Deployed",
+		ClusterInstanceConfiguration: &client.ClusterInstanceConfiguration{Tag: "v1"},
+		CreatedAt:                    createdAt,
+	})
+	expected := InstanceOrder{
+		Id:        types.StringValue("order-1"),
+		Status:    types.StringValue("Deployed"),
+		Tag:       types.StringValue("v1"),
+		CreatedAt: types.StringValue("2023-05-01T10:30:00Z"),
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	got = mapClientOrderToOrder(client.InstanceOrder{ID: "order-2", Status: "Failed"})
+	if !got.Tag.IsNull() || !got.CreatedAt.IsNull() {
+		t.Errorf("expected null tag and created_at, got %v", got)
+	}
+}
+
+func TestValidateRollbackOrder(t *testing.T) {
+	ctx := context.Background()
+
+	orders, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: instanceOrderAttrTypes}, []InstanceOrder{
+		mapClientOrderToOrder(client.InstanceOrder{ID: "order-1", Status: "Deployed"}),
+		mapClientOrderToOrder(client.InstanceOrder{ID: "order-2", Status: "Deployed"}),
+	})
+	if diags.HasError() {
+		t.Fatalf("unable to build orders: %v", diags)
+	}
+	noOrders := types.ListNull(types.ObjectType{AttrTypes: instanceOrderAttrTypes})
+
+	testCases := map[string]struct {
+		order      types.String
+		stateOrder types.String
+		orders     types.List
+		wantErr    string
+	}{
+		"unset":           {order: types.StringNull(), orders: orders},
+		"unchanged":       {order: types.StringValue("order-9"), stateOrder: types.StringValue("order-9"), orders: orders},
+		"known order":     {order: types.StringValue("order-1"), orders: orders},
+		"unknown order":   {order: types.StringValue("order-9"), orders: orders, wantErr: "Order order-9 is not part of the instance history. Available orders are: order-1, order-2."},
+		"new instance":    {order: types.StringValue("order-1"), orders: noOrders, wantErr: "Rollback order can only be set on an existing instance."},
+		"unknown planned": {order: types.StringUnknown(), orders: noOrders},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := validateRollbackOrder(ctx, tc.order, tc.stateOrder, tc.orders)
+			if tc.wantErr == "" && diags.HasError() {
+				t.Errorf("unexpected error %v", diags)
+			}
+			if tc.wantErr != "" && (!diags.HasError() || diags.Errors()[0].Detail() != tc.wantErr) {
+				t.Errorf("expected %q, got %v", tc.wantErr, diags)
+			}
+		})
+	}
+}
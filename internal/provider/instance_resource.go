@@ -4,31 +4,42 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	"terraform-provider-spheron/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &InstanceResource{}
 var _ resource.ResourceWithImportState = &InstanceResource{}
+var _ resource.ResourceWithModifyPlan = &InstanceResource{}
 
 func NewInstanceResource() resource.Resource {
 	return &InstanceResource{}
@@ -36,27 +47,43 @@ func NewInstanceResource() resource.Resource {
 
 type InstanceResource struct {
 	client *client.SpheronApi
+	budget *spendBudget
+	token  *tokenCheck
 }
 
 type InstanceResourceModel struct {
-	Image             types.String `tfsdk:"image"`
-	Tag               types.String `tfsdk:"tag"`
-	ClusterName       types.String `tfsdk:"cluster_name"`
-	Ports             []Port       `tfsdk:"ports"`
-	Env               []Env        `tfsdk:"env"`
-	EnvSecret         []Env        `tfsdk:"env_secret"`
-	Commands          []string     `tfsdk:"commands"`
-	Args              []string     `tfsdk:"args"`
-	Region            types.String `tfsdk:"region"`
-	MachineImage      types.String `tfsdk:"machine_image"`
-	Id                types.String `tfsdk:"id"`
-	HealthCheck       types.Object `tfsdk:"health_check"`
-	Storage           types.Int64  `tfsdk:"storage"`
-	Cpu               types.String `tfsdk:"cpu"`
-	Memory            types.String `tfsdk:"memory"`
-	Replicas          types.Int64  `tfsdk:"replicas"`
-	PersistentStorage types.Object `tfsdk:"persistent_storage"`
-	ComputeType       types.String `tfsdk:"compute_type"`
+	Image                   types.String  `tfsdk:"image"`
+	Tag                     types.String  `tfsdk:"tag"`
+	ClusterName             types.String  `tfsdk:"cluster_name"`
+	Ports                   []Port        `tfsdk:"ports"`
+	Env                     []Env         `tfsdk:"env"`
+	EnvSecret               []Env         `tfsdk:"env_secret"`
+	Commands                []string      `tfsdk:"commands"`
+	Args                    []string      `tfsdk:"args"`
+	Region                  types.String  `tfsdk:"region"`
+	MachineImage            types.String  `tfsdk:"machine_image"`
+	Id                      types.String  `tfsdk:"id"`
+	HealthCheck             types.Object  `tfsdk:"health_check"`
+	Storage                 types.Int64   `tfsdk:"storage"`
+	Cpu                     types.String  `tfsdk:"cpu"`
+	Memory                  types.String  `tfsdk:"memory"`
+	Replicas                types.Int64   `tfsdk:"replicas"`
+	PersistentStorage       types.Object  `tfsdk:"persistent_storage"`
+	Volumes                 []Volume      `tfsdk:"volume"`
+	Gpu                     types.Object  `tfsdk:"gpu"`
+	ComputeType             types.String  `tfsdk:"compute_type"`
+	EstimatedCostPerHour    types.Float64 `tfsdk:"estimated_cost_per_hour"`
+	EstimatedCostPerMonth   types.Float64 `tfsdk:"estimated_cost_per_month"`
+	RetrievableAkt          types.Int64   `tfsdk:"retrievable_akt"`
+	WithdrawnAkt            types.Int64   `tfsdk:"withdrawn_akt"`
+	EscrowTopUp             types.Int64   `tfsdk:"escrow_top_up"`
+	DesiredState            types.String  `tfsdk:"desired_state"`
+	CloseTimeout            types.Int64   `tfsdk:"close_timeout"`
+	WithdrawOnDestroy       types.Bool    `tfsdk:"withdraw_on_destroy"`
+	PreventDestroyIfDomains types.Bool    `tfsdk:"prevent_destroy_if_domains"`
+	Orders                  types.List    `tfsdk:"orders"`
+	RollbackToOrder         types.String  `tfsdk:"rollback_to_order"`
+	AutoRollback            types.Bool    `tfsdk:"auto_rollback"`
 }
 
 type Port struct {
@@ -74,12 +101,54 @@ type HealthCheck struct {
 	Path types.String `tfsdk:"path"`
 }
 
+type InstanceOrder struct {
+	Id        types.String `tfsdk:"id"`
+	Status    types.String `tfsdk:"status"`
+	Tag       types.String `tfsdk:"tag"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+var instanceOrderAttrTypes = map[string]attr.Type{
+	"id":         types.StringType,
+	"status":     types.StringType,
+	"tag":        types.StringType,
+	"created_at": types.StringType,
+}
+
 type PersistentStorage struct {
 	Class      types.String `tfsdk:"class"`
 	MountPoint types.String `tfsdk:"mount_point"`
 	Size       types.Int64  `tfsdk:"size"`
 }
 
+var persistentStorageAttrTypes = map[string]attr.Type{
+	"class":       types.StringType,
+	"mount_point": types.StringType,
+	"size":        types.Int64Type,
+}
+
+type Volume struct {
+	Name       types.String `tfsdk:"name"`
+	Class      types.String `tfsdk:"class"`
+	MountPoint types.String `tfsdk:"mount_point"`
+	Size       types.Int64  `tfsdk:"size"`
+	ReadOnly   types.Bool   `tfsdk:"read_only"`
+}
+
+type Gpu struct {
+	Count  types.Int64  `tfsdk:"count"`
+	Vendor types.String `tfsdk:"vendor"`
+	Model  types.String `tfsdk:"model"`
+	Vram   types.Int64  `tfsdk:"vram"`
+}
+
+var gpuAttrTypes = map[string]attr.Type{
+	"count":  types.Int64Type,
+	"vendor": types.StringType,
+	"model":  types.StringType,
+	"vram":   types.Int64Type,
+}
+
 func (r *InstanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_instance"
 }
@@ -116,45 +185,39 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 			"cpu": schema.StringAttribute{
-				MarkdownDescription: "Instance CPU. Available values [0.5, 1, 2, 4, 8, 16, 32]",
+				MarkdownDescription: "Instance CPU in cores, like 0.5 or 2, or in millicores, like 500m.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfCpuChanged,
+						"Changing the CPU to a different size requires a new instance.",
+						"Changing the CPU to a different size requires a new instance.",
+					),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf(
-						"0.5",
-						"1",
-						"2",
-						"4",
-						"8",
-						"16",
-						"32",
-					),
+					stringvalidator.RegexMatches(cpuPattern, "must be a number of cores, like 0.5, or millicores, like 500m"),
+					positiveSize{parse: parseCpu},
 					stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("memory")),
 					stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
 				},
 			},
 			"memory": schema.StringAttribute{
-				MarkdownDescription: "Instance Memory in GB. Available values [0.5, 1, 2, 4, 8, 16, 32]",
+				MarkdownDescription: "Instance Memory in GB, like 2, or with a unit, like 512Mi or 1.5Gi.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfMemoryChanged,
+						"Changing the memory to a different size requires a new instance.",
+						"Changing the memory to a different size requires a new instance.",
+					),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf(
-						"0.5",
-						"1",
-						"2",
-						"4",
-						"8",
-						"16",
-						"32",
-					),
+					stringvalidator.RegexMatches(memoryPattern, "must be a size in GB, like 2, or with a unit, like 512Mi or 1.5Gi"),
+					positiveSize{parse: parseMemory},
 					stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cpu")),
 					stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
 				},
@@ -220,6 +283,7 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 						"value": schema.StringAttribute{
 							MarkdownDescription: "Environment variable value.",
 							Required:            true,
+							Sensitive:           true,
 						},
 					},
 				},
@@ -273,8 +337,71 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 				Optional: true,
 			},
+			"gpu": schema.SingleNestedAttribute{
+				MarkdownDescription: "GPUs that will be attached to each instance replica. Requires cpu and memory to be set.",
+				Attributes: map[string]schema.Attribute{
+					"count": schema.Int64Attribute{
+						MarkdownDescription: "Number of GPUs per replica. Value cannot exceed 8",
+						Required:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+							int64validator.AtMost(8),
+						},
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+					"vendor": schema.StringAttribute{
+						MarkdownDescription: "GPU vendor. Available values [nvidia, amd]. Read from the GPU catalog when not set.",
+						Optional:            true,
+						Computed:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(
+								"nvidia",
+								"amd",
+							),
+						},
+						PlanModifiers: []planmodifier.String{
+							gpuCatalogValue{},
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"model": schema.StringAttribute{
+						MarkdownDescription: "GPU model, like a100 or rtx4090. Available models are listed by the spheron_gpu_models data source.",
+						Required:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"vram": schema.Int64Attribute{
+						MarkdownDescription: "GPU memory in GB, used to pick a variant of the model. Read from the GPU catalog when not set.",
+						Optional:            true,
+						Computed:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+						PlanModifiers: []planmodifier.Int64{
+							gpuCatalogValue{},
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+				},
+				Optional: true,
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cpu")),
+					objectvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfGpuAddedOrRemoved,
+						"Adding or removing GPUs requires a new instance.",
+						"Adding or removing GPUs requires a new instance.",
+					),
+				},
+			},
 			"persistent_storage": schema.SingleNestedAttribute{
-				MarkdownDescription: "Persistent storage that will be attached to the instance.",
+				MarkdownDescription: "Persistent storage that will be attached to the instance. Deprecated, use volume blocks instead.",
+				DeprecationMessage:  "Use volume blocks instead. A persistent_storage value can be moved to a volume block with the same class, mount_point and size.",
 				Attributes: map[string]schema.Attribute{
 					"class": schema.StringAttribute{
 						MarkdownDescription: "Storage class. Available classes are HDD, SSD and NVMe",
@@ -308,6 +435,9 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 					},
 				},
 				Optional: true,
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("volume")),
+				},
 				PlanModifiers: []planmodifier.Object{
 					objectplanmodifier.RequiresReplace(),
 				},
@@ -326,6 +456,102 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 					),
 				},
 			},
+			"estimated_cost_per_hour": schema.Float64Attribute{
+				MarkdownDescription: "Estimated instance cost in USD per hour, calculated during plan.",
+				Computed:            true,
+			},
+			"estimated_cost_per_month": schema.Float64Attribute{
+				MarkdownDescription: "Estimated instance cost in USD per month, calculated during plan.",
+				Computed:            true,
+			},
+			"retrievable_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT left in the instance escrow that can still be retrieved.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"withdrawn_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT already withdrawn from the instance escrow.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"escrow_top_up": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT to deposit into the instance escrow. The deposit is made when the value is set or changed, so change it to top up again.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"desired_state": schema.StringAttribute{
+				MarkdownDescription: "Desired instance state. Available values [running, stopped]. Stopped instances keep their id, domains and persistent storage configuration. Defaults to running.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("running"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						"running",
+						"stopped",
+					),
+				},
+			},
+			"close_timeout": schema.Int64Attribute{
+				MarkdownDescription: "Time in minutes to wait for the instance to be closed on destroy. Defaults to 5.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(5),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"withdraw_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Withdraw the retrievable AKT from the instance escrow after the instance is closed. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"prevent_destroy_if_domains": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to close the instance while domains are attached to it. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"orders": schema.ListNestedAttribute{
+				MarkdownDescription: "Deployment history of the instance, oldest first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Id of the order.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Status of the order.",
+							Computed:            true,
+						},
+						"tag": schema.StringAttribute{
+							MarkdownDescription: "The tag of docker image deployed by the order.",
+							Computed:            true,
+						},
+						"created_at": schema.StringAttribute{
+							MarkdownDescription: "Time the order was created, in RFC3339 format.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"rollback_to_order": schema.StringAttribute{
+				MarkdownDescription: "Id of a previous order to roll back to. When set or changed, the instance is redeployed with the tag, environment variables, commands and args of that order.",
+				Optional:            true,
+			},
+			"auto_rollback": schema.BoolAttribute{
+				MarkdownDescription: "Redeploy the previous order when a new deployment fails its health check. Requires health_check. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Id of the instance.",
 				Optional:            true,
@@ -335,6 +561,75 @@ func (r *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"volume": schema.ListNestedBlock{
+				MarkdownDescription: "Persistent volume attached to the instance. Adding or removing volumes requires a new instance.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfVolumeAddedOrRemoved,
+						"Adding or removing volumes requires a new instance.",
+						"Adding or removing volumes requires a new instance.",
+					),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the volume, unique within the instance.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z][a-z0-9-]*$`), "must start with a lowercase letter and contain only lowercase letters, digits and dashes"),
+							},
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.RequiresReplace(),
+							},
+						},
+						"class": schema.StringAttribute{
+							MarkdownDescription: "Storage class. Available classes are HDD, SSD and NVMe.",
+							Required:            true,
+							Validators: []validator.String{stringvalidator.OneOf(
+								"HDD",
+								"SSD",
+								"NVMe",
+							)},
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.RequiresReplace(),
+							},
+						},
+						"mount_point": schema.StringAttribute{
+							MarkdownDescription: "Path at which the volume is mounted, unique within the instance.",
+							Required:            true,
+							PlanModifiers: []planmodifier.String{
+								stringplanmodifier.RequiresReplace(),
+							},
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "Volume size in GB. Value cannot exceed 1024GB. Growing a volume resizes it in place, shrinking it requires a new instance.",
+							Required:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+								int64validator.AtMost(1024),
+							},
+							PlanModifiers: []planmodifier.Int64{
+								int64planmodifier.RequiresReplaceIf(
+									requiresReplaceIfVolumeShrunk,
+									"Shrinking a volume requires a new instance.",
+									"Shrinking a volume requires a new instance.",
+								),
+							},
+						},
+						"read_only": schema.BoolAttribute{
+							MarkdownDescription: "Mount the volume read-only. Defaults to false.",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+							PlanModifiers: []planmodifier.Bool{
+								boolplanmodifier.RequiresReplace(),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -343,21 +638,28 @@ func (r *InstanceResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SpheronApi)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.budget = data.budget
+	r.token = data.token
 }
 
 func (r *InstanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan InstanceResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
@@ -366,7 +668,9 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	organization, err := r.client.GetOrganization()
+	ctx = maskSensitiveValues(ctx, getEnvValues(plan.EnvSecret)...)
+
+	organization, err := r.client.GetOrganization(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get organization",
@@ -381,18 +685,25 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		Storage: fmt.Sprintf("%dGi", int(plan.Storage.ValueInt64())),
 	}
 
-	if !plan.PersistentStorage.IsNull() {
-		var persistentStorage PersistentStorage
-		plan.PersistentStorage.As(ctx, &persistentStorage, opts)
-
-		value, _ := GetPersistentStorageClassEnum(persistentStorage.Class.ValueString())
-
-		customSpecs.PersistentStorage = &client.PersistentStorage{
-			Class:      value,
-			MountPoint: persistentStorage.MountPoint.ValueString(),
-			Size:       fmt.Sprintf("%dGi", int(persistentStorage.Size.ValueInt64())),
+	volumes, ok := getPersistentStorageSpecs(ctx, plan.PersistentStorage, plan.Volumes)
+	if !ok {
+		volumesPath := path.Root("volume")
+		if !plan.PersistentStorage.IsNull() {
+			volumesPath = path.Root("persistent_storage")
 		}
+		resp.Diagnostics.AddAttributeError(volumesPath, "Invalid volume configuration.", "The volumes must be known to deploy the instance.")
+		return
+	}
+	customSpecs.SetVolumes(volumes)
+	gpuSpecs, ok, diags := getGpuSpecs(ctx, plan.Gpu)
+	resp.Diagnostics.Append(diags...)
+	if !ok && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddAttributeError(path.Root("gpu"), "Invalid GPU configuration.", "The GPU count and model must be known to deploy the instance.")
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	customSpecs.Gpu = gpuSpecs
 
 	topicId := uuid.New()
 
@@ -411,8 +722,8 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	}
 
 	if plan.MachineImage.ValueString() == "" {
-		customSpecs.CPU = plan.Cpu.ValueString()
-		customSpecs.Memory = fmt.Sprintf("%sGi", plan.Memory.ValueString())
+		customSpecs.CPU = formatCpu(plan.Cpu.ValueString())
+		customSpecs.Memory = formatMemory(plan.Memory.ValueString())
 
 		plan.MachineImage = types.StringValue("Custom Plan")
 	} else {
@@ -439,7 +750,7 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		createRequest.HealthCheckPort = healthCheck.Port.String()
 	}
 
-	response, err := r.client.CreateClusterInstance(createRequest)
+	response, err := r.client.CreateClusterInstance(ctx, createRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -468,8 +779,8 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" {
-		order, err := r.client.GetClusterInstanceOrder(response.ClusterInstanceOrderID)
+	if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" || !plan.Gpu.IsNull() {
+		order, err := r.client.GetClusterInstanceOrder(ctx, response.ClusterInstanceOrderID)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Instance doesn't have provisioned deployments.",
@@ -478,13 +789,55 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 			return
 		}
 
-		plan.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
-		plan.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+		if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" {
+			plan.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
+			plan.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+		}
+		if !plan.Gpu.IsNull() {
+			plan.Gpu = mapClientGpuToGpu(order.ClusterInstanceConfiguration.AgreedMachineImage.Gpu)
+		}
 	}
 
 	plan.Id = types.StringValue(response.ClusterInstanceID)
 	plan.Ports = mapModelPortToPort(ports)
 
+	resp.Diagnostics.Append(topUpInstanceEscrow(ctx, r.client, response.ClusterInstanceID, plan.EscrowTopUp, types.Int64Null())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err := r.client.GetClusterInstance(ctx, response.ClusterInstanceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Coudnt fetch instance by provided id.",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	plan.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	orders, diags := r.getInstanceOrders(ctx, instance)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Orders = orders
+
+	if plan.DesiredState.ValueString() == "stopped" {
+		if err := r.stopInstance(ctx, response.ClusterInstanceID); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to stop instance.",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -494,6 +847,16 @@ func (r *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		tflog.Debug(ctx, "Spheron client is not configured yet, keeping prior state")
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state InstanceResourceModel
 	tflog.Debug(ctx, "Preparing to read item resource")
 	diags := req.State.Get(ctx, &state)
@@ -502,6 +865,8 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	ctx = maskSensitiveValues(ctx, getEnvValues(state.EnvSecret)...)
+
 	if state.Id.ValueString() == "" {
 		resp.Diagnostics.AddError(
 			"Id not provided. Unable to get instance details.",
@@ -510,7 +875,7 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(state.Id.ValueString())
+	instance, err := r.client.GetClusterInstance(ctx, state.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Coudnt fetch instance by provided id.",
@@ -525,7 +890,35 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	state.DesiredState = types.StringValue("running")
+	if instance.State == "Stopped" {
+		state.DesiredState = types.StringValue("stopped")
+	}
+
+	if state.CloseTimeout.IsNull() {
+		state.CloseTimeout = types.Int64Value(5)
+	}
+	if state.WithdrawOnDestroy.IsNull() {
+		state.WithdrawOnDestroy = types.BoolValue(false)
+	}
+	if state.PreventDestroyIfDomains.IsNull() {
+		state.PreventDestroyIfDomains = types.BoolValue(false)
+	}
+	if state.AutoRollback.IsNull() {
+		state.AutoRollback = types.BoolValue(false)
+	}
+
+	state.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	state.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	orders, diags := r.getInstanceOrders(ctx, instance)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Orders = orders
+
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Instance doesn't have provisioned deployments.",
@@ -534,7 +927,7 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	cluster, err := r.client.GetCluster(instance.Cluster)
+	cluster, err := r.client.GetCluster(ctx, instance.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Instance cluster not found.",
@@ -555,12 +948,13 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	state.Tag = types.StringValue(order.ClusterInstanceConfiguration.Tag)
 	state.Replicas = types.Int64Value(int64(order.ClusterInstanceConfiguration.InstanceCount))
 
-	numberStr := RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Storage) // Remove the last two characters ("Gi")
+	numberStr := RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Storage)
 	number, _ := strconv.Atoi(numberStr)
 	state.Storage = types.Int64Value(int64(number))
 
-	state.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
-	state.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+	state.Memory = getMemoryValue(state.Memory, order.ClusterInstanceConfiguration.AgreedMachineImage.Memory)
+	state.Cpu = getCpuValue(state.Cpu, order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu)
+	state.Gpu = mapClientGpuToGpu(order.ClusterInstanceConfiguration.AgreedMachineImage.Gpu)
 
 	if instance.HealthCheck.Port != (client.Port{}) {
 		hcTypes := make(map[string]attr.Type)
@@ -575,43 +969,38 @@ func (r *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		state.HealthCheck = types.ObjectValueMust(hcTypes, hcValues)
 	}
 
-	if order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage != nil &&
-		order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage.Class != "" {
-		var pStorage = order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage
-
-		psTypes := make(map[string]attr.Type)
-		psValues := make(map[string]attr.Value)
-
-		psTypes["class"] = types.StringType
-		psTypes["mount_point"] = types.StringType
-		psTypes["size"] = types.Int64Type
-
-		value, _ := GetStorageClassFromValue(pStorage.Class)
-
-		psValues["class"] = types.StringValue(value)
-		psValues["mount_point"] = types.StringValue(pStorage.MountPoint)
-
-		numberStr := pStorage.Size[:len(pStorage.Size)-2] // Remove the last two characters ("Gi")
-		number, _ := strconv.Atoi(numberStr)
-		psValues["size"] = types.Int64Value(int64(number))
-
-		state.PersistentStorage = types.ObjectValueMust(psTypes, psValues)
+	// Instances configured with the deprecated persistent_storage keep it, everything else is read as volumes.
+	volumes := order.ClusterInstanceConfiguration.AgreedMachineImage.GetVolumes()
+	if !state.PersistentStorage.IsNull() && len(volumes) == 1 {
+		state.PersistentStorage = mapClientPersistentStorage(volumes[0])
+		state.Volumes = []Volume{}
+	} else {
+		state.PersistentStorage = types.ObjectNull(persistentStorageAttrTypes)
+		state.Volumes = mapClientPersistentStorageToVolumes(volumes)
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan InstanceResourceModel
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state InstanceResourceModel
 
 	// Retrieve values from plan
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	organization, err := r.client.GetOrganization()
+	ctx = maskSensitiveValues(ctx, append(getEnvValues(plan.EnvSecret), getEnvValues(state.EnvSecret)...)...)
+
+	organization, err := r.client.GetOrganization(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get organization",
@@ -620,6 +1009,16 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	if plan.DesiredState.ValueString() == "running" && state.DesiredState.ValueString() == "stopped" {
+		if err := r.startInstance(ctx, plan.Id.ValueString(), organization.ID); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to start instance.",
+				err.Error(),
+			)
+			return
+		}
+	}
+
 	var healthCheck HealthCheck
 	opts := basetypes.ObjectAsOptions{}
 	plan.HealthCheck.As(ctx, &healthCheck, opts)
@@ -629,7 +1028,7 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		HealthCheckPort: int(healthCheck.Port.ValueInt64()),
 	}
 
-	_, err = r.client.UpdateClusterInstanceHealthCheckInfo(plan.Id.ValueString(), hcUpdate)
+	_, err = r.client.UpdateClusterInstanceHealthCheckInfo(ctx, plan.Id.ValueString(), hcUpdate)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -639,7 +1038,29 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(plan.Id.ValueString())
+	// Volumes are only updated in place when they grow, any other change replaces the instance.
+	stateVolumes := map[string]Volume{}
+	for _, volume := range state.Volumes {
+		stateVolumes[volume.Name.ValueString()] = volume
+	}
+	for i, volume := range plan.Volumes {
+		stateVolume, ok := stateVolumes[volume.Name.ValueString()]
+		if !ok || volume.Size.ValueInt64() <= stateVolume.Size.ValueInt64() {
+			continue
+		}
+
+		_, err = r.client.ResizeClusterInstanceVolume(ctx, plan.Id.ValueString(), volume.Name.ValueString(), fmt.Sprintf("%dGi", int(volume.Size.ValueInt64())))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("volume").AtListIndex(i).AtName("size"),
+				"Unable to resize volume.",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	instance, err := r.client.GetClusterInstance(ctx, plan.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Coudnt fetch instance by provided id.",
@@ -648,7 +1069,7 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Instance doesn't have provisioned deployments.",
@@ -664,7 +1085,32 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 	envEqual := reflect.DeepEqual(envs, order.ClusterInstanceConfiguration.Env)
 	tagEqual := plan.Tag.ValueString() == order.ClusterInstanceConfiguration.Tag
 
-	if !argsEqual || !commandEqual || !envEqual || !tagEqual {
+	if !plan.RollbackToOrder.IsNull() && !plan.RollbackToOrder.Equal(state.RollbackToOrder) {
+		rollbackOrder, err := r.client.GetClusterInstanceOrder(ctx, plan.RollbackToOrder.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rollback_to_order"),
+				"Order not found.",
+				err.Error(),
+			)
+			return
+		}
+
+		if err := r.redeployOrder(ctx, plan.Id.ValueString(), organization.ID, rollbackOrder); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to roll back instance.",
+				err.Error(),
+			)
+			return
+		}
+
+		// Terraform only accepts the configured values in the state of a successful apply, so the order is
+		// reported and the next refresh reads its values.
+		resp.Diagnostics.AddWarning(
+			"Instance was rolled back.",
+			fmt.Sprintf("Instance was rolled back to order %s with tag %s. Update tag, env, env_secret, commands and args to match the order, otherwise the next apply deploys the configured values again.", rollbackOrder.ID, rollbackOrder.ClusterInstanceConfiguration.Tag),
+		)
+	} else if !argsEqual || !commandEqual || !envEqual || !tagEqual {
 		topicId := uuid.New()
 
 		updateRequest := client.UpdateInstanceRequest{
@@ -676,7 +1122,7 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 			OrganizationID: organization.ID,
 		}
 
-		_, err = r.client.UpdateClusterInstance(plan.Id.ValueString(), updateRequest)
+		response, err := r.client.UpdateClusterInstance(ctx, plan.Id.ValueString(), updateRequest)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to update instance.",
@@ -694,8 +1140,65 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 			)
 			return
 		}
+
+		if plan.AutoRollback.ValueBool() && !plan.HealthCheck.IsNull() {
+			if err := r.waitForHealthCheck(ctx, plan.Id.ValueString(), response.ClusterInstanceOrderID); err != nil {
+				if rollbackErr := r.redeployOrder(ctx, plan.Id.ValueString(), organization.ID, order); rollbackErr != nil {
+					resp.Diagnostics.AddError(
+						"Unable to roll back instance.",
+						fmt.Sprintf("%s Rolling back to order %s failed: %s", err.Error(), order.ID, rollbackErr.Error()),
+					)
+					return
+				}
+
+				resp.Diagnostics.AddError(
+					"Instance health check failed.",
+					fmt.Sprintf("%s Instance was rolled back to order %s.", err.Error(), order.ID),
+				)
+				resp.Diagnostics.Append(r.setRolledBackState(ctx, plan, order, &resp.State)...)
+				return
+			}
+		}
+	}
+
+	if plan.DesiredState.ValueString() == "stopped" && state.DesiredState.ValueString() != "stopped" {
+		if err := r.stopInstance(ctx, plan.Id.ValueString()); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to stop instance.",
+				err.Error(),
+			)
+			return
+		}
 	}
 
+	resp.Diagnostics.Append(topUpInstanceEscrow(ctx, r.client, plan.Id.ValueString(), plan.EscrowTopUp, state.EscrowTopUp)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err = r.client.GetClusterInstance(ctx, plan.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Coudnt fetch instance by provided id.",
+			err.Error(),
+		)
+		return
+	}
+
+	if plan.RetrievableAkt.IsUnknown() {
+		plan.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	}
+
+	orders, diags := r.getInstanceOrders(ctx, instance)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Orders = orders
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -704,7 +1207,161 @@ func (r *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 	tflog.Debug(ctx, "Updated item resource", map[string]any{"success": true})
 }
 
+func (r *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var state InstanceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		r.budget.release(state.Id)
+		return
+	}
+
+	if r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.checkDeployPermission(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state InstanceResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isEscrowTopUp(plan.EscrowTopUp, state.EscrowTopUp) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("retrievable_akt"), types.Int64Unknown())...)
+	}
+
+	resp.Diagnostics.Append(validateInstanceCatalog(ctx, r.client, plan.Region, state.Region, plan.MachineImage, state.MachineImage)...)
+	resp.Diagnostics.Append(validateRollbackOrder(ctx, plan.RollbackToOrder, state.RollbackToOrder, state.Orders)...)
+	resp.Diagnostics.Append(validateVolumes(plan.Volumes)...)
+	if isPlannedChange(plan.Cpu, state.Cpu) || isPlannedChange(plan.Memory, state.Memory) {
+		resp.Diagnostics.Append(validateInstanceSize(ctx, r.client, plan.Cpu, plan.Memory, path.Root("cpu"), path.Root("memory"))...)
+	}
+	if !plan.Gpu.Equal(state.Gpu) || !plan.Region.Equal(state.Region) || !plan.Replicas.Equal(state.Replicas) {
+		resp.Diagnostics.Append(validateInstanceGpu(ctx, r.client, plan.Region, plan.Gpu, plan.Replicas)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() && plan.Region.Equal(state.Region) && plan.MachineImage.Equal(state.MachineImage) &&
+		plan.Cpu.Equal(state.Cpu) && plan.Memory.Equal(state.Memory) && plan.Storage.Equal(state.Storage) &&
+		plan.Replicas.Equal(state.Replicas) && plan.PersistentStorage.Equal(state.PersistentStorage) &&
+		reflect.DeepEqual(plan.Volumes, state.Volumes) && plan.Gpu.Equal(state.Gpu) && plan.ComputeType.Equal(state.ComputeType) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_hour"), state.EstimatedCostPerHour)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_month"), state.EstimatedCostPerMonth)...)
+		resp.Diagnostics.Append(r.budget.reserve(ctx, state.Id, plan.ClusterName.ValueString(), state.EstimatedCostPerHour)...)
+		return
+	}
+
+	costPerHour := types.Float64Unknown()
+	volumes, volumesKnown := getPersistentStorageSpecs(ctx, plan.PersistentStorage, plan.Volumes)
+	priceRequest, ok := getInstancePriceRequest(ctx, plan.Region, plan.MachineImage, plan.Cpu, plan.Memory, plan.Storage, plan.Replicas, volumes, plan.Gpu, plan.ComputeType.ValueString() == "DEMAND")
+	if ok && volumesKnown && !plan.ComputeType.IsUnknown() {
+		var costPerMonth types.Float64
+		var diags diag.Diagnostics
+
+		costPerHour, costPerMonth, diags = estimateInstanceCost(ctx, r.client, priceRequest)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_hour"), costPerHour)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_month"), costPerMonth)...)
+	}
+
+	resp.Diagnostics.Append(r.budget.reserve(ctx, state.Id, plan.ClusterName.ValueString(), costPerHour)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Region.IsUnknown() {
+		return
+	}
+
+	spec, ok := r.getPlannedInstanceSpec(ctx, plan)
+	if !ok {
+		return
+	}
+
+	regions, err := r.client.GetRegions(ctx)
+	if err != nil {
+		return
+	}
+
+	if err := checkRegionCapacity(regions, plan.Region.ValueString(), spec); err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("region"),
+			"Requested instance spec may not be available in region.",
+			err.Error(),
+		)
+	}
+}
+
+func (r *InstanceResource) getPlannedInstanceSpec(ctx context.Context, plan InstanceResourceModel) (instanceSpec, bool) {
+	volumes, ok := getPersistentStorageSpecs(ctx, plan.PersistentStorage, plan.Volumes)
+	if !ok || plan.Replicas.IsUnknown() || plan.Storage.IsUnknown() || plan.MachineImage.IsUnknown() {
+		return instanceSpec{}, false
+	}
+
+	var spec instanceSpec
+	machineImage := plan.MachineImage.ValueString()
+	if machineImage != "" && machineImage != "Custom Plan" {
+		machines, err := r.client.GetComputeMachines(ctx)
+		if err != nil {
+			return instanceSpec{}, false
+		}
+
+		found := false
+		for _, machine := range machines {
+			if machine.Name == machineImage {
+				spec.Cpu = float64(machine.Cpu)
+				spec.Memory, err = parseMemory(machine.Memory)
+				found = err == nil
+				break
+			}
+		}
+		if !found {
+			return instanceSpec{}, false
+		}
+	} else {
+		if plan.Cpu.IsUnknown() || plan.Memory.IsUnknown() {
+			return instanceSpec{}, false
+		}
+
+		var err error
+		if spec.Cpu, err = parseCpu(plan.Cpu.ValueString()); err != nil {
+			return instanceSpec{}, false
+		}
+		if spec.Memory, err = parseMemory(plan.Memory.ValueString()); err != nil {
+			return instanceSpec{}, false
+		}
+	}
+
+	spec.Storage = float64(plan.Storage.ValueInt64())
+	for _, volume := range volumes {
+		size, _ := strconv.Atoi(RemoveGiSuffix(volume.Size))
+		spec.Storage += float64(size)
+	}
+
+	replicas := float64(plan.Replicas.ValueInt64())
+	spec.Cpu *= replicas
+	spec.Memory *= replicas
+	spec.Storage *= replicas
+
+	return spec, true
+}
+
 func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Preparing to delete item resource")
 	var state InstanceResourceModel
 
@@ -715,17 +1372,254 @@ func (r *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	_, err := r.client.CloseClusterInstance(state.Id.ValueString())
-	if err != nil && err.Error() != "Instance already closed" {
-		resp.Diagnostics.AddError(
-			"Unable to destroy Instance",
-			err.Error(),
-		)
+	resp.Diagnostics.Append(destroyClusterInstance(ctx, r.client, state.Id.ValueString(), destroyInstanceOptions{
+		timeout:          time.Duration(state.CloseTimeout.ValueInt64()) * time.Minute,
+		withdraw:         state.WithdrawOnDestroy.ValueBool(),
+		preventIfDomains: state.PreventDestroyIfDomains.ValueBool(),
+	})...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 	tflog.Debug(ctx, "Instance closed", map[string]any{"success": true})
 }
 
+func (r *InstanceResource) stopInstance(ctx context.Context, id string) error {
+	if _, err := r.client.StopClusterInstance(ctx, id); err != nil {
+		return err
+	}
+
+	return waitForInstanceState(ctx, r.client, id, "Stopped", instanceStopTimeout)
+}
+
+func (r *InstanceResource) startInstance(ctx context.Context, id string, organizationID string) error {
+	topicId := uuid.New()
+
+	_, err := r.client.StartClusterInstance(ctx, id, client.StartInstanceRequest{
+		UniqueTopicID:  topicId.String(),
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.WaitForDeployedEvent(ctx, topicId.String())
+	return err
+}
+
+func (r *InstanceResource) redeployOrder(ctx context.Context, id string, organizationID string, order client.InstanceOrder) error {
+	if order.ClusterInstanceConfiguration == nil {
+		return fmt.Errorf("Order %s has no instance configuration.", order.ID)
+	}
+
+	topicId := uuid.New()
+
+	_, err := r.client.UpdateClusterInstance(ctx, id, client.UpdateInstanceRequest{
+		Env:            order.ClusterInstanceConfiguration.Env,
+		Command:        order.ClusterInstanceConfiguration.Command,
+		Args:           order.ClusterInstanceConfiguration.Args,
+		UniqueTopicID:  topicId.String(),
+		Tag:            order.ClusterInstanceConfiguration.Tag,
+		OrganizationID: organizationID,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.WaitForDeployedEvent(ctx, topicId.String())
+	return err
+}
+
+// setRolledBackState writes the state of an instance that was rolled back to order, with the tag, env, commands and args of that order.
+func (r *InstanceResource) setRolledBackState(ctx context.Context, plan InstanceResourceModel, order client.InstanceOrder, state *tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	plan.Tag = types.StringValue(order.ClusterInstanceConfiguration.Tag)
+	plan.Env = mapClientEnvsToEnvs(order.ClusterInstanceConfiguration.Env, false)
+	plan.EnvSecret = mapClientEnvsToEnvs(order.ClusterInstanceConfiguration.Env, true)
+	plan.Args = order.ClusterInstanceConfiguration.Args
+	plan.Commands = order.ClusterInstanceConfiguration.Command
+
+	instance, err := r.client.GetClusterInstance(ctx, plan.Id.ValueString())
+	if err != nil {
+		diags.AddError("Coudnt fetch instance by provided id.", err.Error())
+		return diags
+	}
+
+	plan.DesiredState = types.StringValue("running")
+	if instance.State == "Stopped" {
+		plan.DesiredState = types.StringValue("stopped")
+	}
+	plan.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	plan.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	orders, orderDiags := r.getInstanceOrders(ctx, instance)
+	diags.Append(orderDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	plan.Orders = orders
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
+	diags.Append(state.Set(ctx, plan)...)
+	return diags
+}
+
+func (r *InstanceResource) waitForHealthCheck(ctx context.Context, id string, orderID string) error {
+	order, err := r.client.GetClusterInstanceOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(instanceHealthCheckTimeout)
+
+	for {
+		instance, err := r.client.GetClusterInstance(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if instance.HealthCheck.Status != "" && !instance.HealthCheck.Timestamp.Before(order.CreatedAt) {
+			if instance.HealthCheck.Status == "Healthy" {
+				return nil
+			}
+			return fmt.Errorf("Health check of order %s reported status %s.", orderID, instance.HealthCheck.Status)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Health check of order %s did not complete within %s.", orderID, instanceHealthCheckTimeout)
+		}
+
+		tflog.Debug(ctx, "Waiting for instance health check", map[string]any{"instance": id, "order": orderID})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(instanceStatePollInterval):
+		}
+	}
+}
+
+func (r *InstanceResource) getInstanceOrders(ctx context.Context, instance client.Instance) (types.List, diag.Diagnostics) {
+	orders := make([]InstanceOrder, 0, len(instance.Orders))
+	for _, id := range instance.Orders {
+		order, err := r.client.GetClusterInstanceOrder(ctx, id)
+		if err != nil {
+			var diags diag.Diagnostics
+			diags.AddError("Unable to get instance orders.", err.Error())
+			return types.ListNull(types.ObjectType{AttrTypes: instanceOrderAttrTypes}), diags
+		}
+
+		orders = append(orders, mapClientOrderToOrder(order))
+	}
+
+	return types.ListValueFrom(ctx, types.ObjectType{AttrTypes: instanceOrderAttrTypes}, orders)
+}
+
 func (r *InstanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+const (
+	instanceStatePollInterval  = 5 * time.Second
+	instanceStopTimeout        = 5 * time.Minute
+	instanceHealthCheckTimeout = 5 * time.Minute
+)
+
+type destroyInstanceOptions struct {
+	timeout          time.Duration
+	withdraw         bool
+	preventIfDomains bool
+}
+
+func destroyClusterInstance(ctx context.Context, api *client.SpheronApi, instanceID string, options destroyInstanceOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if options.preventIfDomains {
+		domains, err := api.GetClusterInstanceDomains(ctx, instanceID)
+		if err != nil {
+			diags.AddError("Unable to get instance domains.", err.Error())
+			return diags
+		}
+
+		if len(domains) != 0 {
+			names := make([]string, 0, len(domains))
+			for _, domain := range domains {
+				names = append(names, domain.Name)
+			}
+
+			diags.AddError(
+				"Instance has attached domains.",
+				fmt.Sprintf("Instance %s has domains %s attached. Remove the domains or set prevent_destroy_if_domains to false before destroying it.", instanceID, strings.Join(names, ", ")),
+			)
+			return diags
+		}
+	}
+
+	_, err := api.CloseClusterInstance(ctx, instanceID)
+	if err != nil && err.Error() != "Instance already closed" {
+		diags.AddError("Unable to destroy instance.", err.Error())
+		return diags
+	}
+
+	if err := waitForInstanceState(ctx, api, instanceID, "Closed", options.timeout); err != nil {
+		diags.AddError("Instance was not closed.", err.Error())
+		return diags
+	}
+
+	if options.withdraw {
+		if _, err := api.WithdrawClusterInstanceEscrow(ctx, instanceID); err != nil {
+			diags.AddError("Unable to withdraw instance escrow.", err.Error())
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// isEscrowTopUp reports whether escrow_top_up was set or changed, which triggers a new deposit.
+func isEscrowTopUp(amount types.Int64, previous types.Int64) bool {
+	return !amount.IsNull() && !amount.IsUnknown() && !amount.Equal(previous)
+}
+
+func topUpInstanceEscrow(ctx context.Context, api *client.SpheronApi, instanceID string, amount types.Int64, previous types.Int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !isEscrowTopUp(amount, previous) {
+		return diags
+	}
+
+	if _, err := api.TopUpClusterInstanceEscrow(ctx, instanceID, int(amount.ValueInt64())); err != nil {
+		diags.AddAttributeError(path.Root("escrow_top_up"), "Unable to top up instance escrow.", err.Error())
+	}
+
+	return diags
+}
+
+func waitForInstanceState(ctx context.Context, api *client.SpheronApi, instanceID string, state string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		instance, err := api.GetClusterInstance(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+
+		if instance.State == state {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Instance %s did not reach state %s within %s. Current state is %s.", instanceID, state, timeout, instance.State)
+		}
+
+		tflog.Debug(ctx, "Waiting for instance state", map[string]any{"instance": instanceID, "state": instance.State, "desired": state})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(instanceStatePollInterval):
+		}
+	}
+}
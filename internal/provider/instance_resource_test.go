@@ -0,0 +1,859 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"terraform-provider-spheron/internal/client/fake"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccInstanceResource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceConfig("latest"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("spheron_instance.test", "id"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "latest"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "machine_image", "Custom Plan"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "cpu", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "memory", "2"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "ports.0.container_port", "8000"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "ports.0.exposed_port", "80"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "env.#", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "estimated_cost_per_hour", "0.022"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "estimated_cost_per_month", "15.84"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "retrievable_akt", "5000000"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "withdrawn_akt", "0"),
+				),
+			},
+			{
+				ResourceName:            "spheron_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_type", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceConfig("v2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_machineImage(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "spheron_instance" "test" {
+  image         = "crccheck/hello-world"
+  tag           = "latest"
+  cluster_name  = "tf_test_machine"
+  region        = "any"
+  machine_image = "Ventus Small"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  replicas     = 1
+  compute_type = "DEMAND"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "machine_image", "Ventus Small"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "cpu", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "memory", "2"),
+					resource.TestCheckResourceAttrSet("spheron_instance.test", "ports.0.exposed_port"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_desiredState(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	var instanceID string
+
+	testAccCheckInstanceState := func(expected string) resource.TestCheckFunc {
+		return resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+			if instanceID == "" {
+				instanceID = value
+			}
+			if value != instanceID {
+				return fmt.Errorf("expected instance %s to be kept, got %s", instanceID, value)
+			}
+
+			instance, _ := server.Instance(value)
+			if instance.State != expected {
+				return fmt.Errorf("expected instance state %s, got %s", expected, instance.State)
+			}
+			return nil
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceDesiredStateConfig("running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "desired_state", "running"),
+					testAccCheckInstanceState("Active"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceDesiredStateConfig("stopped"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "desired_state", "stopped"),
+					testAccCheckInstanceState("Stopped"),
+				),
+			},
+			{
+				ResourceName:            "spheron_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_type", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceDesiredStateConfig("running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "desired_state", "running"),
+					testAccCheckInstanceState("Active"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceDesiredStateConfig(desiredState string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image         = "crccheck/hello-world"
+  tag           = "latest"
+  cluster_name  = "tf_test_desired_state"
+  region        = "any"
+  desired_state = %q
+
+  ports = [
+    {
+      container_port = 8000
+      exposed_port   = 80
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, desiredState)
+}
+
+func TestAccInstanceResource_destroy(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	var instanceID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			instance, ok := server.Instance(instanceID)
+			if !ok || instance.State != "Closed" {
+				return fmt.Errorf("expected instance %s to be closed, got %+v", instanceID, instance)
+			}
+			if instance.RetrievableAkt != 0 || instance.WithdrawnAkt != fake.DefaultEscrowDeposit {
+				return fmt.Errorf("expected escrow to be withdrawn, got retrievable %d and withdrawn %d", instance.RetrievableAkt, instance.WithdrawnAkt)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceDestroyConfig(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "close_timeout", "5"),
+					resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+						instanceID = value
+						server.AddDomain(value, "console.example.com")
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceDestroyConfig(true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`has domains console.example.com attached`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceDestroyConfig(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "prevent_destroy_if_domains", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceDestroyConfig(preventIfDomains bool) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_destroy"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+
+  withdraw_on_destroy        = true
+  prevent_destroy_if_domains = %t
+}
+`, preventIfDomains)
+}
+
+func TestAccInstanceResource_escrowTopUp(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceEscrowTopUpConfig(1000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "escrow_top_up", "1000"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "retrievable_akt", fmt.Sprint(fake.DefaultEscrowDeposit+1000)),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceEscrowTopUpConfig(2500),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "escrow_top_up", "2500"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "retrievable_akt", fmt.Sprint(fake.DefaultEscrowDeposit+3500)),
+				),
+			},
+			{
+				Config:   testAccProviderConfig + testAccInstanceResourceEscrowTopUpConfig(2500),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceEscrowTopUpConfig(amount int) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_escrow_top_up"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+
+  escrow_top_up = %d
+}
+`, amount)
+}
+
+func TestAccInstanceResource_rollback(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.UnhealthyTags = []string{"v2"}
+
+	testAccCheckActiveTag := func(expected string) resource.TestCheckFunc {
+		return resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+			instance, _ := server.Instance(value)
+			order, _ := server.Order(instance.ActiveOrder)
+			if order.ClusterInstanceConfiguration.Tag != expected {
+				return fmt.Errorf("expected active tag %s, got %s", expected, order.ClusterInstanceConfiguration.Tag)
+			}
+			return nil
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceRollbackConfig("v1", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "auto_rollback", "true"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.#", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.0.id", "order-3"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.0.status", "Deployed"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.0.tag", "v1"),
+					resource.TestCheckResourceAttrSet("spheron_instance.test", "orders.0.created_at"),
+				),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceRollbackConfig("v2", ""),
+				ExpectError: regexp.MustCompile(`(?s)Instance health check failed.*rolled\s+back\s+to\s+order\s+order-3`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceRollbackConfig("v1", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.#", "3"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.1.tag", "v2"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.2.tag", "v1"),
+					testAccCheckActiveTag("v1"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceRollbackConfig("v3", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.#", "4"),
+					testAccCheckActiveTag("v3"),
+				),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceRollbackConfig("v3", "order-99"),
+				ExpectError: regexp.MustCompile(`Order order-99 is not part of the instance history`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceRollbackConfig("v3", "order-3"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v3"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "rollback_to_order", "order-3"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.#", "5"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.4.tag", "v1"),
+					testAccCheckActiveTag("v1"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				RefreshState: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "orders.#", "5"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceRollbackConfig("v1", "order-3"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceRollbackConfig(tag string, rollbackToOrder string) string {
+	rollback := ""
+	if rollbackToOrder != "" {
+		rollback = fmt.Sprintf("rollback_to_order = %q", rollbackToOrder)
+	}
+
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image         = "crccheck/hello-world"
+  tag           = %q
+  cluster_name  = "tf_test_rollback"
+  region        = "any"
+  auto_rollback = true
+  %s
+
+  ports = [
+    {
+      container_port = 8000
+      exposed_port   = 80
+    }
+  ]
+
+  health_check = {
+    path = "/"
+    port = 8000
+  }
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, tag, rollback)
+}
+
+func TestAccInstanceResource_invalidCatalog(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceCatalogConfig("us-eats", "Ventus Small"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Region "us-eats" is not available. Did you mean "us-east"\?`),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceCatalogConfig("any", "Ventus Smal"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Machine image "Ventus Smal" is not available. Did you\s+mean "Ventus Small"\?`),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_gpu(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceGpuConfig("latest", "a100", "vram = 24"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`GPU model "a100" is available with 40, 80 GB of VRAM.`),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceGpuConfig("latest", "h100", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`GPU model "h100" is not available. Did you mean "a100"\?`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceGpuConfig("latest", "a100", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.count", "1"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.model", "a100"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.vendor", "nvidia"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.vram", "40"),
+					resource.TestMatchResourceAttr("spheron_instance.test", "estimated_cost_per_hour", regexp.MustCompile(`^1\.158`)),
+				),
+			},
+			{
+				ResourceName:            "spheron_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_type", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceGpuConfig("v2", "a100", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "v2"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.vram", "40"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceGpuConfig("v2", "rtx4090", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.model", "rtx4090"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "gpu.vram", "24"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_sizing(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceSizingConfig("2 cores", "1Gi"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be a number of cores, like 0.5, or millicores, like 500m`),
+			},
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceSizingConfig("64", "128Gi"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)CPU 64 is outside of the allowed range of 0.1 to 32 cores.*Memory 128Gi is\s+outside of the allowed range of 0.125Gi to 64Gi.`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceSizingConfig("500m", "512Mi"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "cpu", "500m"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "memory", "512Mi"),
+					resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+						instance, _ := server.Instance(value)
+						order, _ := server.Order(instance.ActiveOrder)
+						machineImage := order.ClusterInstanceConfiguration.AgreedMachineImage
+						if machineImage.Cpu != 0.5 || machineImage.Memory != "0.5Gi" {
+							return fmt.Errorf("expected 0.5 CPU and 0.5Gi memory, got %g and %s", machineImage.Cpu, machineImage.Memory)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:            "spheron_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_type", "cpu", "memory", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceSizingConfig("0.5", "0.5Gi"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "cpu", "0.5"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "memory", "0.5Gi"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceSizingConfig("0.5", "0.5Gi"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceSizingConfig("1", "0.5Gi"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_volumes(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	checkVolumeSizes := func(sizes ...string) resource.TestCheckFunc {
+		return resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+			instance, _ := server.Instance(value)
+			order, _ := server.Order(instance.ActiveOrder)
+			volumes := order.ClusterInstanceConfiguration.AgreedMachineImage.Volumes
+			if len(volumes) != len(sizes) {
+				return fmt.Errorf("expected %d volumes, got %+v", len(sizes), volumes)
+			}
+			for i, volume := range volumes {
+				if volume.Size != sizes[i] {
+					return fmt.Errorf("expected volume %s to be %s, got %s", volume.Name, sizes[i], volume.Size)
+				}
+			}
+			return nil
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccInstanceResourceVolumesConfig(100, 20, "/var/lib/postgresql/data"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Mount point "/var/lib/postgresql/data" is used by more than one volume.`),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceVolumesConfig(100, 20, "/var/lib/postgresql/wal"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.#", "2"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.0.name", "data"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.0.read_only", "false"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.1.class", "SSD"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.1.size", "20"),
+					checkVolumeSizes("100Gi", "20Gi"),
+				),
+			},
+			{
+				ResourceName:            "spheron_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_type", "estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceVolumesConfig(200, 20, "/var/lib/postgresql/wal"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.0.size", "200"),
+					checkVolumeSizes("200Gi", "20Gi"),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceVolumesConfig(200, 20, "/var/lib/postgresql/wal"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourceVolumesConfig(200, 10, "/var/lib/postgresql/wal"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: checkVolumeSizes("200Gi", "10Gi"),
+			},
+		},
+	})
+}
+
+func TestAccInstanceResource_persistentStorage(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccInstanceResourcePersistentStorageConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_instance.test", "persistent_storage.class", "HDD"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "persistent_storage.size", "5"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "volume.#", "0"),
+					resource.TestCheckResourceAttrWith("spheron_instance.test", "id", func(value string) error {
+						instance, _ := server.Instance(value)
+						order, _ := server.Order(instance.ActiveOrder)
+						machineImage := order.ClusterInstanceConfiguration.AgreedMachineImage
+						if machineImage.PersistentStorage == nil || len(machineImage.Volumes) != 0 {
+							return fmt.Errorf("expected a single persistentStorage, got %+v and volumes %+v", machineImage.PersistentStorage, machineImage.Volumes)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccInstanceResourcePersistentStorageConfig(),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccInstanceResourceVolumesConfig(dataSize int, walSize int, walMountPoint string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "postgres"
+  tag          = "15"
+  cluster_name = "tf_test_volumes"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 5432
+    }
+  ]
+
+  volume {
+    name        = "data"
+    class       = "NVMe"
+    mount_point = "/var/lib/postgresql/data"
+    size        = %[1]d
+  }
+
+  volume {
+    name        = "wal"
+    class       = "SSD"
+    mount_point = %[3]q
+    size        = %[2]d
+  }
+
+  storage      = 10
+  cpu          = 2
+  memory       = 4
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, dataSize, walSize, walMountPoint)
+}
+
+func testAccInstanceResourcePersistentStorageConfig() string {
+	return `
+resource "spheron_instance" "test" {
+  image        = "postgres"
+  tag          = "15"
+  cluster_name = "tf_test_persistent_storage"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 5432
+    }
+  ]
+
+  persistent_storage = {
+    class       = "HDD"
+    mount_point = "/var/lib/postgresql/data"
+    size        = 5
+  }
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`
+}
+
+func testAccInstanceResourceSizingConfig(cpu string, memory string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = "latest"
+  cluster_name = "tf_test_sizing"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  cpu          = %[1]q
+  memory       = %[2]q
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, cpu, memory)
+}
+
+func testAccInstanceResourceGpuConfig(tag string, model string, extra string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = %[1]q
+  cluster_name = "tf_test_gpu"
+  region       = "us-east"
+
+  ports = [
+    {
+      container_port = 8000
+      exposed_port   = 80
+    }
+  ]
+
+  gpu = {
+    count = 1
+    model = %[2]q
+    %[3]s
+  }
+
+  storage      = 10
+  cpu          = 2
+  memory       = 8
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, tag, model, extra)
+}
+
+func testAccInstanceResourceCatalogConfig(region string, machineImage string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image         = "crccheck/hello-world"
+  tag           = "latest"
+  cluster_name  = "tf_test_catalog"
+  region        = %[1]q
+  machine_image = %[2]q
+
+  ports = [
+    {
+      container_port = 8000
+    }
+  ]
+
+  storage      = 10
+  replicas     = 1
+  compute_type = "DEMAND"
+}
+`, region, machineImage)
+}
+
+func testAccInstanceResourceConfig(tag string) string {
+	return fmt.Sprintf(`
+resource "spheron_instance" "test" {
+  image        = "crccheck/hello-world"
+  tag          = %[1]q
+  cluster_name = "tf_test"
+  region       = "any"
+
+  ports = [
+    {
+      container_port = 8000
+      exposed_port   = 80
+    }
+  ]
+
+  env = [
+    {
+      key   = "k"
+      value = "v"
+    }
+  ]
+
+  storage      = 10
+  cpu          = 1
+  memory       = 2
+  replicas     = 1
+  compute_type = "SPOT"
+}
+`, tag)
+}
@@ -4,22 +4,24 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	"terraform-provider-spheron/internal/client"
 
@@ -29,6 +31,7 @@ import (
 
 var _ resource.Resource = &MarketplaceInstanceResource{}
 var _ resource.ResourceWithImportState = &MarketplaceInstanceResource{}
+var _ resource.ResourceWithModifyPlan = &MarketplaceInstanceResource{}
 
 func NewMarketplaceInstanceResource() resource.Resource {
 	return &MarketplaceInstanceResource{}
@@ -36,20 +39,32 @@ func NewMarketplaceInstanceResource() resource.Resource {
 
 type MarketplaceInstanceResource struct {
 	client *client.SpheronApi
+	budget *spendBudget
+	token  *tokenCheck
 }
 
 type MarketplaceInstanceResourceModel struct {
-	Region            types.String `tfsdk:"region"`
-	Name              types.String `tfsdk:"name"`
-	MachineImage      types.String `tfsdk:"machine_image"`
-	Ports             types.List   `tfsdk:"ports"`
-	Env               types.Set    `tfsdk:"env"`
-	Id                types.String `tfsdk:"id"`
-	Cpu               types.String `tfsdk:"cpu"`
-	Memory            types.String `tfsdk:"memory"`
-	Storage           types.Int64  `tfsdk:"storage"`
-	Replicas          types.Int64  `tfsdk:"replicas"`
-	PersistentStorage types.Object `tfsdk:"persistent_storage"`
+	Region                  types.String  `tfsdk:"region"`
+	Name                    types.String  `tfsdk:"name"`
+	MachineImage            types.String  `tfsdk:"machine_image"`
+	Ports                   types.List    `tfsdk:"ports"`
+	Env                     types.Set     `tfsdk:"env"`
+	Id                      types.String  `tfsdk:"id"`
+	Cpu                     types.String  `tfsdk:"cpu"`
+	Memory                  types.String  `tfsdk:"memory"`
+	Storage                 types.Int64   `tfsdk:"storage"`
+	Replicas                types.Int64   `tfsdk:"replicas"`
+	PersistentStorage       types.Object  `tfsdk:"persistent_storage"`
+	Gpu                     types.Object  `tfsdk:"gpu"`
+	TemplateVersion         types.String  `tfsdk:"template_version"`
+	EstimatedCostPerHour    types.Float64 `tfsdk:"estimated_cost_per_hour"`
+	EstimatedCostPerMonth   types.Float64 `tfsdk:"estimated_cost_per_month"`
+	RetrievableAkt          types.Int64   `tfsdk:"retrievable_akt"`
+	WithdrawnAkt            types.Int64   `tfsdk:"withdrawn_akt"`
+	EscrowTopUp             types.Int64   `tfsdk:"escrow_top_up"`
+	CloseTimeout            types.Int64   `tfsdk:"close_timeout"`
+	WithdrawOnDestroy       types.Bool    `tfsdk:"withdraw_on_destroy"`
+	PreventDestroyIfDomains types.Bool    `tfsdk:"prevent_destroy_if_domains"`
 }
 
 func (r *MarketplaceInstanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -100,45 +115,39 @@ func (r *MarketplaceInstanceResource) Schema(ctx context.Context, req resource.S
 				},
 			},
 			"cpu": schema.StringAttribute{
-				MarkdownDescription: "Instance CPU. Value cannot exceed 1024GB",
+				MarkdownDescription: "Instance CPU in cores, like 0.5 or 2, or in millicores, like 500m.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfCpuChanged,
+						"Changing the CPU to a different size requires a new instance.",
+						"Changing the CPU to a different size requires a new instance.",
+					),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf(
-						"0.5",
-						"1",
-						"2",
-						"4",
-						"8",
-						"16",
-						"32",
-					),
+					stringvalidator.RegexMatches(cpuPattern, "must be a number of cores, like 0.5, or millicores, like 500m"),
+					positiveSize{parse: parseCpu},
 					stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("memory")),
 					stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
 				},
 			},
 			"memory": schema.StringAttribute{
-				MarkdownDescription: "Instance Memory in GB.",
+				MarkdownDescription: "Instance Memory in GB, like 2, or with a unit, like 512Mi or 1.5Gi.",
 				Optional:            true,
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfMemoryChanged,
+						"Changing the memory to a different size requires a new instance.",
+						"Changing the memory to a different size requires a new instance.",
+					),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf(
-						"0.5",
-						"1",
-						"2",
-						"4",
-						"8",
-						"16",
-						"32",
-					),
+					stringvalidator.RegexMatches(memoryPattern, "must be a size in GB, like 2, or with a unit, like 512Mi or 1.5Gi"),
+					positiveSize{parse: parseMemory},
 					stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cpu")),
 					stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
 				},
@@ -154,6 +163,68 @@ func (r *MarketplaceInstanceResource) Schema(ctx context.Context, req resource.S
 					int64planmodifier.RequiresReplace(),
 				},
 			},
+			"gpu": schema.SingleNestedAttribute{
+				MarkdownDescription: "GPUs that will be attached to each instance replica. Requires cpu and memory to be set.",
+				Attributes: map[string]schema.Attribute{
+					"count": schema.Int64Attribute{
+						MarkdownDescription: "Number of GPUs per replica. Value cannot exceed 8",
+						Required:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+							int64validator.AtMost(8),
+						},
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+					"vendor": schema.StringAttribute{
+						MarkdownDescription: "GPU vendor. Available values [nvidia, amd]. Read from the GPU catalog when not set.",
+						Optional:            true,
+						Computed:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(
+								"nvidia",
+								"amd",
+							),
+						},
+						PlanModifiers: []planmodifier.String{
+							gpuCatalogValue{},
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"model": schema.StringAttribute{
+						MarkdownDescription: "GPU model, like a100 or rtx4090. Available models are listed by the spheron_gpu_models data source.",
+						Required:            true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"vram": schema.Int64Attribute{
+						MarkdownDescription: "GPU memory in GB, used to pick a variant of the model. Read from the GPU catalog when not set.",
+						Optional:            true,
+						Computed:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+						PlanModifiers: []planmodifier.Int64{
+							gpuCatalogValue{},
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+				},
+				Optional: true,
+				Validators: []validator.Object{
+					objectvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("cpu")),
+					objectvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("machine_image")),
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplaceIf(
+						requiresReplaceIfGpuAddedOrRemoved,
+						"Adding or removing GPUs requires a new instance.",
+						"Adding or removing GPUs requires a new instance.",
+					),
+				},
+			},
 			"persistent_storage": schema.SingleNestedAttribute{
 				MarkdownDescription: "Persistent storage that will be attached to the instance.",
 				Attributes: map[string]schema.Attribute{
@@ -194,7 +265,7 @@ func (r *MarketplaceInstanceResource) Schema(ctx context.Context, req resource.S
 				},
 			},
 			"env": schema.SetNestedAttribute{
-				MarkdownDescription: "The list of environmetnt variables. NOTE: Some marketplace apps have required env variables that must be provided.",
+				MarkdownDescription: "The list of environmetnt variables. NOTE: Some marketplace apps have required env variables that must be provided. Optional variables that are not set use the marketplace app default value.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"key": schema.StringAttribute{
@@ -208,8 +279,13 @@ func (r *MarketplaceInstanceResource) Schema(ctx context.Context, req resource.S
 					},
 				},
 				Optional: true,
-				PlanModifiers: []planmodifier.Set{
-					setplanmodifier.RequiresReplace(),
+			},
+			"template_version": schema.StringAttribute{
+				MarkdownDescription: "Marketplace app version to deploy. Defaults to the version currently published for the app. Changing it upgrades the instance in place.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 			"ports": schema.ListNestedAttribute{
@@ -237,6 +313,56 @@ func (r *MarketplaceInstanceResource) Schema(ctx context.Context, req resource.S
 					listplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"estimated_cost_per_hour": schema.Float64Attribute{
+				MarkdownDescription: "Estimated instance cost in USD per hour, calculated during plan.",
+				Computed:            true,
+			},
+			"estimated_cost_per_month": schema.Float64Attribute{
+				MarkdownDescription: "Estimated instance cost in USD per month, calculated during plan.",
+				Computed:            true,
+			},
+			"retrievable_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT left in the instance escrow that can still be retrieved.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"withdrawn_akt": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT already withdrawn from the instance escrow.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"escrow_top_up": schema.Int64Attribute{
+				MarkdownDescription: "Amount of AKT to deposit into the instance escrow. The deposit is made when the value is set or changed, so change it to top up again.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"close_timeout": schema.Int64Attribute{
+				MarkdownDescription: "Time in minutes to wait for the instance to be closed on destroy. Defaults to 5.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(5),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"withdraw_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Withdraw the retrievable AKT from the instance escrow after the instance is closed. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"prevent_destroy_if_domains": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to close the instance while domains are attached to it. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Id or the instance.",
 				Computed:            true,
@@ -253,21 +379,28 @@ func (r *MarketplaceInstanceResource) Configure(ctx context.Context, req resourc
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SpheronApi)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.budget = data.budget
+	r.token = data.token
 }
 
 func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan MarketplaceInstanceResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
@@ -276,7 +409,7 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	organization, err := r.client.GetOrganization()
+	organization, err := r.client.GetOrganization(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get organization",
@@ -285,7 +418,7 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	marketplaceApps, err := r.client.GetClusterTemplates()
+	marketplaceApps, err := r.client.GetClusterTemplates(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get available markeplace apps.",
@@ -321,20 +454,21 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 		Storage: fmt.Sprintf("%dGi", int(plan.Storage.ValueInt64())),
 	}
 
-	if !plan.PersistentStorage.IsNull() {
-		var persistentStorage PersistentStorage
-		opts := basetypes.ObjectAsOptions{}
-
-		plan.PersistentStorage.As(ctx, &persistentStorage, opts)
-
-		value, _ := GetPersistentStorageClassEnum(persistentStorage.Class.ValueString())
-
-		customSpecs.PersistentStorage = &client.PersistentStorage{
-			Class:      value,
-			MountPoint: persistentStorage.MountPoint.ValueString(),
-			Size:       fmt.Sprintf("%dGi", int(persistentStorage.Size.ValueInt64())),
-		}
+	volumes, ok := getPersistentStorageSpecs(ctx, plan.PersistentStorage, nil)
+	if !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("persistent_storage"), "Invalid volume configuration.", "The persistent storage must be known to deploy the instance.")
+		return
 	}
+	customSpecs.SetVolumes(volumes)
+	gpuSpecs, ok, diags := getGpuSpecs(ctx, plan.Gpu)
+	resp.Diagnostics.Append(diags...)
+	if !ok && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddAttributeError(path.Root("gpu"), "Invalid GPU configuration.", "The GPU count and model must be known to deploy the instance.")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	customSpecs.Gpu = gpuSpecs
 
 	instanceConfig := client.CreateInstanceFromMarketplaceRequest{
 		TemplateID:           chosenMarketplaceApp.ID,
@@ -345,14 +479,20 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 		InstanceCount:        int(plan.Replicas.ValueInt64()),
 	}
 
+	if plan.TemplateVersion.IsUnknown() || plan.TemplateVersion.IsNull() {
+		plan.TemplateVersion = types.StringValue(chosenMarketplaceApp.ServiceData.DockerImageTag)
+	} else {
+		instanceConfig.Tag = plan.TemplateVersion.ValueString()
+	}
+
 	if plan.MachineImage.ValueString() == "" {
-		customSpecs.CPU = plan.Cpu.ValueString()
-		customSpecs.Memory = fmt.Sprintf("%sGi", plan.Memory.ValueString())
+		customSpecs.CPU = formatCpu(plan.Cpu.ValueString())
+		customSpecs.Memory = formatMemory(plan.Memory.ValueString())
 
 		plan.MachineImage = types.StringValue("Custom Plan")
 	} else {
 
-		computeMachines, err := r.client.GetComputeMachines()
+		computeMachines, err := r.client.GetComputeMachines(ctx)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to get fetch available compute machines.",
@@ -375,7 +515,7 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 
 	instanceConfig.CustomInstanceSpecs = customSpecs
 
-	response, err := r.client.CreateClusterInstanceFromTemplate(instanceConfig)
+	response, err := r.client.CreateClusterInstanceFromTemplate(ctx, instanceConfig)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -407,8 +547,8 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 	plan.Id = types.StringValue(response.ClusterInstanceID)
 	plan.Ports = types.ListValueMust(types.ObjectType{AttrTypes: getPortAtrTypes()}, mapModelPortToPortValue(ports))
 
-	if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" {
-		order, err := r.client.GetClusterInstanceOrder(response.ClusterInstanceOrderID)
+	if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" || !plan.Gpu.IsNull() {
+		order, err := r.client.GetClusterInstanceOrder(ctx, response.ClusterInstanceOrderID)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Instance doesn't have provisioned deployments.",
@@ -417,10 +557,35 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 			return
 		}
 
-		plan.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
-		plan.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+		if plan.Cpu.ValueString() == "" || plan.Memory.ValueString() == "" {
+			plan.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
+			plan.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+		}
+		if !plan.Gpu.IsNull() {
+			plan.Gpu = mapClientGpuToGpu(order.ClusterInstanceConfiguration.AgreedMachineImage.Gpu)
+		}
 	}
 
+	resp.Diagnostics.Append(topUpInstanceEscrow(ctx, r.client, response.ClusterInstanceID, plan.EscrowTopUp, types.Int64Null())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err := r.client.GetClusterInstance(ctx, response.ClusterInstanceID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Coudnt fetch instance by provided id.",
+			err.Error(),
+		)
+		return
+	}
+
+	plan.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	plan.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -431,6 +596,16 @@ func (r *MarketplaceInstanceResource) Create(ctx context.Context, req resource.C
 }
 
 func (r *MarketplaceInstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		tflog.Debug(ctx, "Spheron client is not configured yet, keeping prior state")
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state MarketplaceInstanceResourceModel
 	tflog.Debug(ctx, "Preparing to read item resource.")
 	diags := req.State.Get(ctx, &state)
@@ -447,7 +622,7 @@ func (r *MarketplaceInstanceResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	instance, err := r.client.GetClusterInstance(state.Id.ValueString())
+	instance, err := r.client.GetClusterInstance(ctx, state.Id.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Coudnt fetch instance by provided id.",
@@ -462,7 +637,20 @@ func (r *MarketplaceInstanceResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	cluster, err := r.client.GetCluster(instance.Cluster)
+	if state.CloseTimeout.IsNull() {
+		state.CloseTimeout = types.Int64Value(5)
+	}
+	if state.WithdrawOnDestroy.IsNull() {
+		state.WithdrawOnDestroy = types.BoolValue(false)
+	}
+	if state.PreventDestroyIfDomains.IsNull() {
+		state.PreventDestroyIfDomains = types.BoolValue(false)
+	}
+
+	state.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	state.WithdrawnAkt = types.Int64Value(int64(instance.WithdrawnAkt))
+
+	cluster, err := r.client.GetCluster(ctx, instance.Cluster)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Instance cluster not found.",
@@ -471,7 +659,7 @@ func (r *MarketplaceInstanceResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	order, err := r.client.GetClusterInstanceOrder(instance.ActiveOrder)
+	order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
 	if err != nil {
 		state.MachineImage = types.StringValue("")
 		state.Region = types.StringValue("")
@@ -488,63 +676,180 @@ func (r *MarketplaceInstanceResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	if len(order.ClusterInstanceConfiguration.Env) != 0 {
-		envs, diag := types.SetValue(types.ObjectType{AttrTypes: getEnvAtrTypes()}, mapClientEnvsToEnvsValue(order.ClusterInstanceConfiguration.Env, false))
+	clientEnvs := order.ClusterInstanceConfiguration.Env
+
+	marketplaceApps, err := r.client.GetClusterTemplates(ctx)
+	if err == nil {
+		if marketplaceApp, err := findMarketplaceAppByName(marketplaceApps, cluster.Name); err == nil {
+			stateEnvs := make([]Env, 0, len(state.Env.Elements()))
+			state.Env.ElementsAs(ctx, &stateEnvs, false)
+
+			configured := make(map[string]bool, len(stateEnvs))
+			for _, env := range stateEnvs {
+				configured[env.Key.ValueString()] = true
+			}
+
+			clientEnvs = filterDefaultDeploymentVariables(marketplaceApp.ServiceData.Variables, clientEnvs, configured)
+		}
+	}
+
+	if envValues := mapClientEnvsToEnvsValue(clientEnvs, false); len(envValues) != 0 {
+		envs, diag := types.SetValue(types.ObjectType{AttrTypes: getEnvAtrTypes()}, envValues)
 		if diag.HasError() {
 			resp.Diagnostics.Append(diag.Errors()...)
 			return
 		}
 
 		state.Env = envs
+	} else {
+		state.Env = types.SetNull(types.ObjectType{AttrTypes: getEnvAtrTypes()})
 	}
 
-	if order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage != nil &&
-		order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage.Class != "" {
-		var pStorage = order.ClusterInstanceConfiguration.AgreedMachineImage.PersistentStorage
-
-		psTypes := make(map[string]attr.Type)
-		psValues := make(map[string]attr.Value)
-
-		psTypes["class"] = types.StringType
-		psTypes["mount_point"] = types.StringType
-		psTypes["size"] = types.Int64Type
-
-		value, _ := GetStorageClassFromValue(pStorage.Class)
-
-		psValues["class"] = types.StringValue(value)
-		psValues["mount_point"] = types.StringValue(pStorage.MountPoint)
-
-		numberStr := pStorage.Size[:len(pStorage.Size)-2] // Remove the last two characters ("Gi")
-		number, _ := strconv.Atoi(numberStr)
-		psValues["size"] = types.Int64Value(int64(number))
-
-		state.PersistentStorage = types.ObjectValueMust(psTypes, psValues)
+	if volumes := order.ClusterInstanceConfiguration.AgreedMachineImage.GetVolumes(); len(volumes) > 0 {
+		state.PersistentStorage = mapClientPersistentStorage(volumes[0])
 	}
 
-	numberStr := order.ClusterInstanceConfiguration.AgreedMachineImage.Storage[:len(order.ClusterInstanceConfiguration.AgreedMachineImage.Storage)-2] // Remove the last two characters ("Gi")
+	numberStr := RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Storage)
 	number, _ := strconv.Atoi(numberStr)
 	state.Storage = types.Int64Value(int64(number))
 
-	state.Memory = types.StringValue(RemoveGiSuffix(order.ClusterInstanceConfiguration.AgreedMachineImage.Memory))
-	state.Cpu = types.StringValue(fmt.Sprint(order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu))
+	state.Memory = getMemoryValue(state.Memory, order.ClusterInstanceConfiguration.AgreedMachineImage.Memory)
+	state.Cpu = getCpuValue(state.Cpu, order.ClusterInstanceConfiguration.AgreedMachineImage.Cpu)
+	state.Gpu = mapClientGpuToGpu(order.ClusterInstanceConfiguration.AgreedMachineImage.Gpu)
 	state.Replicas = types.Int64Value(int64(order.ClusterInstanceConfiguration.InstanceCount))
 	state.Ports = ports
 	state.MachineImage = types.StringValue(order.ClusterInstanceConfiguration.AgreedMachineImage.MachineType)
 	state.Region = types.StringValue(order.ClusterInstanceConfiguration.Region)
 	state.Name = types.StringValue(cluster.Name)
+	state.TemplateVersion = types.StringValue(order.ClusterInstanceConfiguration.Tag)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *MarketplaceInstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan MarketplaceInstanceResourceModel
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state MarketplaceInstanceResourceModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if !plan.Env.Equal(state.Env) || !plan.TemplateVersion.Equal(state.TemplateVersion) {
+		organization, err := r.client.GetOrganization(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get organization",
+				err.Error(),
+			)
+			return
+		}
+
+		marketplaceApps, err := r.client.GetClusterTemplates(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get available markeplace apps.",
+				err.Error(),
+			)
+			return
+		}
+
+		chosenMarketplaceApp, err := findMarketplaceAppByName(marketplaceApps, plan.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to get marketplace app by provided name.",
+				err.Error(),
+			)
+			return
+		}
+
+		envList := make([]Env, 0, len(plan.Env.Elements()))
+		plan.Env.ElementsAs(ctx, &envList, false)
+
+		deploymentEnv, err := checkRequiredDeploymentVariables(chosenMarketplaceApp.ServiceData.Variables, envList)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Required env variable not set!",
+				err.Error(),
+			)
+			return
+		}
+
+		instance, err := r.client.GetClusterInstance(ctx, plan.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Coudnt fetch instance by provided id.",
+				err.Error(),
+			)
+			return
+		}
+
+		order, err := r.client.GetClusterInstanceOrder(ctx, instance.ActiveOrder)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Instance doesn't have provisioned deployments.",
+				err.Error(),
+			)
+			return
+		}
+
+		topicId := uuid.New()
+
+		updateRequest := client.UpdateInstanceRequest{
+			Env:            mapDeploymentVariablesToClientEnvs(chosenMarketplaceApp.ServiceData.Variables, deploymentEnv, order.ClusterInstanceConfiguration.Env),
+			Command:        order.ClusterInstanceConfiguration.Command,
+			Args:           order.ClusterInstanceConfiguration.Args,
+			UniqueTopicID:  topicId.String(),
+			Tag:            plan.TemplateVersion.ValueString(),
+			OrganizationID: organization.ID,
+		}
+
+		_, err = r.client.UpdateClusterInstance(ctx, plan.Id.ValueString(), updateRequest)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to update marketplace instance.",
+				err.Error(),
+			)
+			return
+		}
+
+		_, err = r.client.WaitForDeployedEvent(ctx, topicId.String())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Marketplace instance deployment failed.",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	if isEscrowTopUp(plan.EscrowTopUp, state.EscrowTopUp) {
+		resp.Diagnostics.Append(topUpInstanceEscrow(ctx, r.client, plan.Id.ValueString(), plan.EscrowTopUp, state.EscrowTopUp)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		instance, err := r.client.GetClusterInstance(ctx, plan.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Coudnt fetch instance by provided id.",
+				err.Error(),
+			)
+			return
+		}
+
+		plan.RetrievableAkt = types.Int64Value(int64(instance.RetrievableAkt))
+	}
+
+	plan.EstimatedCostPerHour = float64UnknownAsNull(plan.EstimatedCostPerHour)
+	plan.EstimatedCostPerMonth = float64UnknownAsNull(plan.EstimatedCostPerMonth)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -553,7 +858,141 @@ func (r *MarketplaceInstanceResource) Update(ctx context.Context, req resource.U
 	tflog.Debug(ctx, "Updated item resource", map[string]any{"success": true})
 }
 
+func (r *MarketplaceInstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		var state MarketplaceInstanceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		r.budget.release(state.Id)
+		return
+	}
+
+	if r.client == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.token.checkDeployPermission(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state MarketplaceInstanceResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if isEscrowTopUp(plan.EscrowTopUp, state.EscrowTopUp) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("retrievable_akt"), types.Int64Unknown())...)
+	}
+
+	resp.Diagnostics.Append(validateInstanceCatalog(ctx, r.client, plan.Region, state.Region, plan.MachineImage, state.MachineImage)...)
+	if isPlannedChange(plan.Cpu, state.Cpu) || isPlannedChange(plan.Memory, state.Memory) {
+		resp.Diagnostics.Append(validateInstanceSize(ctx, r.client, plan.Cpu, plan.Memory, path.Root("cpu"), path.Root("memory"))...)
+	}
+	if !plan.Gpu.Equal(state.Gpu) || !plan.Region.Equal(state.Region) || !plan.Replicas.Equal(state.Replicas) {
+		resp.Diagnostics.Append(validateInstanceGpu(ctx, r.client, plan.Region, plan.Gpu, plan.Replicas)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() && plan.Region.Equal(state.Region) && plan.MachineImage.Equal(state.MachineImage) &&
+		plan.Cpu.Equal(state.Cpu) && plan.Memory.Equal(state.Memory) && plan.Storage.Equal(state.Storage) &&
+		plan.Replicas.Equal(state.Replicas) && plan.PersistentStorage.Equal(state.PersistentStorage) && plan.Gpu.Equal(state.Gpu) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_hour"), state.EstimatedCostPerHour)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_month"), state.EstimatedCostPerMonth)...)
+		resp.Diagnostics.Append(r.budget.reserve(ctx, state.Id, plan.Name.ValueString(), state.EstimatedCostPerHour)...)
+	} else {
+		costPerHour := types.Float64Unknown()
+		volumes, volumesKnown := getPersistentStorageSpecs(ctx, plan.PersistentStorage, nil)
+		if priceRequest, ok := getInstancePriceRequest(ctx, plan.Region, plan.MachineImage, plan.Cpu, plan.Memory, plan.Storage, plan.Replicas, volumes, plan.Gpu, false); ok && volumesKnown {
+			var costPerMonth types.Float64
+			var diags diag.Diagnostics
+
+			costPerHour, costPerMonth, diags = estimateInstanceCost(ctx, r.client, priceRequest)
+			resp.Diagnostics.Append(diags...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_hour"), costPerHour)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_cost_per_month"), costPerMonth)...)
+		}
+
+		resp.Diagnostics.Append(r.budget.reserve(ctx, state.Id, plan.Name.ValueString(), costPerHour)...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Name.IsUnknown() || plan.Env.IsUnknown() {
+		return
+	}
+
+	versionChanged := isPlannedChange(plan.TemplateVersion, state.TemplateVersion)
+	if plan.Name.Equal(state.Name) && plan.Env.Equal(state.Env) && !versionChanged {
+		return
+	}
+
+	envList := make([]Env, 0, len(plan.Env.Elements()))
+	resp.Diagnostics.Append(plan.Env.ElementsAs(ctx, &envList, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, env := range envList {
+		if env.Key.IsUnknown() || env.Value.IsUnknown() {
+			return
+		}
+	}
+
+	marketplaceApps, err := r.client.GetClusterTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get available markeplace apps.",
+			err.Error(),
+		)
+		return
+	}
+
+	if err := validateMarketplaceAppName(marketplaceApps, plan.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Unable to get marketplace app by provided name.",
+			err.Error(),
+		)
+		return
+	}
+
+	chosenMarketplaceApp, _ := findMarketplaceAppByName(marketplaceApps, plan.Name.ValueString())
+
+	if _, err := checkRequiredDeploymentVariables(chosenMarketplaceApp.ServiceData.Variables, envList); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("env"),
+			"Invalid marketplace app env variables.",
+			err.Error(),
+		)
+	}
+
+	versions := chosenMarketplaceApp.ServiceData.Versions
+	if versionChanged && len(versions) != 0 {
+		if err := validateCatalogName("Template version", plan.TemplateVersion.ValueString(), versions); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("template_version"),
+				"Invalid marketplace app template version.",
+				err.Error(),
+			)
+		}
+	}
+}
+
 func (r *MarketplaceInstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tflog.Debug(ctx, "Preparing to delete item resource")
 	var state MarketplaceInstanceResourceModel
 
@@ -564,12 +1003,12 @@ func (r *MarketplaceInstanceResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
-	_, err := r.client.CloseClusterInstance(state.Id.ValueString())
-	if err != nil && err.Error() != "Instance already closed" {
-		resp.Diagnostics.AddError(
-			"Unable to destroy marketplace instance",
-			err.Error(),
-		)
+	resp.Diagnostics.Append(destroyClusterInstance(ctx, r.client, state.Id.ValueString(), destroyInstanceOptions{
+		timeout:          time.Duration(state.CloseTimeout.ValueInt64()) * time.Minute,
+		withdraw:         state.WithdrawOnDestroy.ValueBool(),
+		preventIfDomains: state.PreventDestroyIfDomains.ValueBool(),
+	})...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 	tflog.Debug(ctx, "Instance closed", map[string]any{"success": true})
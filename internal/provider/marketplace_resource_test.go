@@ -0,0 +1,292 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+	"terraform-provider-spheron/internal/client/fake"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestAccMarketplaceInstanceResource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "spheron_marketplace_instance" "test" {
+  name          = "Postgres"
+  machine_image = "Ventus Nano"
+  region        = "any"
+
+  env = [
+    {
+      key   = "POSTGRES_PASSWORD"
+      value = "secret"
+    },
+    {
+      key   = "POSTGRES_DB"
+      value = "db"
+    }
+  ]
+
+  storage  = 10
+  replicas = 1
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("spheron_marketplace_instance.test", "id"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "machine_image", "Ventus Nano"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "cpu", "1"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "memory", "0.5"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "ports.0.container_port", "5432"),
+					resource.TestCheckResourceAttrSet("spheron_marketplace_instance.test", "ports.0.exposed_port"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "env.#", "2"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "template_version", "15"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "estimated_cost_per_hour", "0.016"),
+				),
+			},
+			{
+				ResourceName:            "spheron_marketplace_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+		},
+	})
+}
+
+func TestAccMarketplaceInstanceResource_gpu(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "spheron_marketplace_instance" "test" {
+  name     = "IPFS"
+  region   = "us-west"
+  cpu      = 4
+  memory   = 16
+  storage  = 10
+  replicas = 1
+
+  gpu = {
+    count  = 1
+    vendor = "amd"
+    model  = "mi300x"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "gpu.vendor", "amd"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "gpu.model", "mi300x"),
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "gpu.vram", "192"),
+				),
+			},
+			{
+				ResourceName:            "spheron_marketplace_instance.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"estimated_cost_per_hour", "estimated_cost_per_month"},
+			},
+		},
+	})
+}
+
+func TestAccMarketplaceInstanceResource_unknownApp(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+resource "spheron_marketplace_instance" "test" {
+  name     = "Postgress"
+  region   = "any"
+  cpu      = 1
+  memory   = 1
+  storage  = 10
+  replicas = 1
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Marketplace app "Postgress" is not available. Did you mean\s+"Postgres"\?`),
+			},
+		},
+	})
+}
+
+func TestAccMarketplaceInstanceResource_invalidEnv(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig + testAccMarketplaceInstanceEnvConfig("POSTGRES_PASWORD"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Valid variable names are:\s+POSTGRES_PASSWORD, POSTGRES_USER, POSTGRES_DB`),
+			},
+			{
+				Config:      testAccProviderConfig + testAccMarketplaceInstanceEnvConfig("POSTGRES_USER"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing required deployment variable:\s+POSTGRES_PASSWORD`),
+			},
+		},
+	})
+}
+
+func TestAccMarketplaceInstanceResource_update(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	var instanceID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccMarketplaceInstanceVersionConfig("14", "first"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "template_version", "14"),
+					resource.TestCheckResourceAttrWith("spheron_marketplace_instance.test", "id", func(value string) error {
+						instanceID = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccMarketplaceInstanceVersionConfig("16", "second"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_marketplace_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "template_version", "16"),
+					resource.TestCheckResourceAttrWith("spheron_marketplace_instance.test", "id", func(value string) error {
+						if value != instanceID {
+							return fmt.Errorf("expected instance %s to be updated in place, got %s", instanceID, value)
+						}
+
+						instance, _ := server.Instance(value)
+						order, _ := server.Order(instance.ActiveOrder)
+						if order.ClusterInstanceConfiguration.Tag != "16" {
+							return fmt.Errorf("expected deployed tag 16, got %s", order.ClusterInstanceConfiguration.Tag)
+						}
+						if !reflect.DeepEqual(order.ClusterInstanceConfiguration.Env, []client.Env{
+							{Value: "POSTGRES_PASSWORD=second"},
+							{Value: "POSTGRES_USER=postgres"},
+							{Value: "POSTGRES_DB=postgres"},
+						}) {
+							return fmt.Errorf("unexpected deployed env %v", order.ClusterInstanceConfiguration.Env)
+						}
+
+						return nil
+					}),
+				),
+			},
+			{
+				Config:      testAccProviderConfig + testAccMarketplaceInstanceVersionConfig("1.6", "second"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Template version "1.6" is not available. Did you mean "16"\?`),
+			},
+		},
+	})
+}
+
+func TestAccMarketplaceInstanceResource_escrowTopUp(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + testAccMarketplaceInstanceEscrowTopUpConfig(1000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "retrievable_akt", fmt.Sprint(fake.DefaultEscrowDeposit+1000)),
+				),
+			},
+			{
+				Config: testAccProviderConfig + testAccMarketplaceInstanceEscrowTopUpConfig(2500),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_marketplace_instance.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("spheron_marketplace_instance.test", "retrievable_akt", fmt.Sprint(fake.DefaultEscrowDeposit+3500)),
+				),
+			},
+		},
+	})
+}
+
+func testAccMarketplaceInstanceEscrowTopUpConfig(amount int) string {
+	return fmt.Sprintf(`
+resource "spheron_marketplace_instance" "test" {
+  name          = "Postgres"
+  region        = "any"
+  machine_image = "Ventus Nano"
+  storage       = 10
+  replicas      = 1
+  escrow_top_up = %d
+
+  env = [
+    {
+      key   = "POSTGRES_PASSWORD"
+      value = "secret"
+    }
+  ]
+}
+`, amount)
+}
+
+func testAccMarketplaceInstanceVersionConfig(version string, password string) string {
+	return fmt.Sprintf(`
+resource "spheron_marketplace_instance" "test" {
+  name             = "Postgres"
+  region           = "any"
+  machine_image    = "Ventus Nano"
+  template_version = %[1]q
+  storage          = 10
+  replicas         = 1
+
+  env = [
+    {
+      key   = "POSTGRES_PASSWORD"
+      value = %[2]q
+    }
+  ]
+}
+`, version, password)
+}
+
+func testAccMarketplaceInstanceEnvConfig(key string) string {
+	return fmt.Sprintf(`
+resource "spheron_marketplace_instance" "test" {
+  name     = "Postgres"
+  region   = "any"
+  cpu      = 1
+  memory   = 1
+  storage  = 10
+  replicas = 1
+
+  env = [
+    {
+      key   = %q
+      value = "value"
+    }
+  ]
+}
+`, key)
+}
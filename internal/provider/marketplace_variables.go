@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"terraform-provider-spheron/internal/client"
+)
+
+// mapDeploymentVariablesToClientEnvs maps the template variables of a deployment to envs, keeping the secret flag of the current envs.
+func mapDeploymentVariablesToClientEnvs(variables []client.MarketplaceAppVariable, deploymentEnv []client.MarketplaceDeploymentVariable, currentEnvs []client.Env) []client.Env {
+	secrets := make(map[string]bool, len(currentEnvs))
+	for _, env := range currentEnvs {
+		key, _ := splitClientEnv(env.Value)
+		secrets[key] = env.IsSecret
+	}
+
+	envs := make([]client.Env, 0, len(deploymentEnv))
+	for _, deploymentVariable := range deploymentEnv {
+		for _, variable := range variables {
+			if variable.Label == deploymentVariable.Label {
+				envs = append(envs, client.Env{
+					Value:    variable.Name + "=" + deploymentVariable.Value,
+					IsSecret: secrets[variable.Name],
+				})
+			}
+		}
+	}
+
+	return envs
+}
+
+// filterDefaultDeploymentVariables drops env variables that were filled in from the template defaults and were not set in the configuration.
+func filterDefaultDeploymentVariables(appVariables []client.MarketplaceAppVariable, clientEnvs []client.Env, configured map[string]bool) []client.Env {
+	defaults := make(map[string]string, len(appVariables))
+	for _, appVar := range appVariables {
+		defaults[appVar.Name] = appVar.DefaultValue
+	}
+
+	filtered := make([]client.Env, 0, len(clientEnvs))
+	for _, clientEnv := range clientEnvs {
+		key, value := splitClientEnv(clientEnv.Value)
+
+		if defaultValue, ok := defaults[key]; ok && !configured[key] && defaultValue == value {
+			continue
+		}
+
+		filtered = append(filtered, clientEnv)
+	}
+
+	return filtered
+}
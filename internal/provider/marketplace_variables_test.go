@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+)
+
+func TestFilterDefaultDeploymentVariables(t *testing.T) {
+	var response struct {
+		ClusterTemplates []client.MarketplaceApp `json:"clusterTemplates"`
+	}
+	loadJSONFixture(t, "cluster_templates.json", &response)
+
+	clientEnvs := []client.Env{
+		{Value: "POSTGRES_PASSWORD=secret"},
+		{Value: "POSTGRES_USER=postgres"},
+		{Value: "POSTGRES_DB=db"},
+	}
+
+	got := filterDefaultDeploymentVariables(response.ClusterTemplates[0].ServiceData.Variables, clientEnvs, map[string]bool{"POSTGRES_PASSWORD": true})
+
+	expected := []client.Env{
+		{Value: "POSTGRES_PASSWORD=secret"},
+		{Value: "POSTGRES_DB=db"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	got = filterDefaultDeploymentVariables(response.ClusterTemplates[0].ServiceData.Variables, clientEnvs, map[string]bool{"POSTGRES_USER": true})
+	if len(got) != 3 {
+		t.Errorf("expected configured default variable to be kept, got %v", got)
+	}
+}
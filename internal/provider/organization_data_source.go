@@ -19,6 +19,7 @@ func NewOrganizationDataSource() datasource.DataSource {
 
 type OrganizationDataSource struct {
 	client *client.SpheronApi
+	token  *tokenCheck
 }
 
 type SpheronDataSourceModel struct {
@@ -51,16 +52,23 @@ func (d *OrganizationDataSource) Configure(ctx context.Context, req datasource.C
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.SpheronApi)
+	data, ok := req.ProviderData.(*providerData)
 	if !ok {
 		tflog.Error(ctx, "Unable to prepare Spheron API client.")
 		return
 	}
-	d.client = client
+	d.client = data.client
+	d.token = data.token
 }
 
 func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	tflog.Debug(ctx, "Preparing to read item data source.")
+
+	resp.Diagnostics.Append(d.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state SpheronDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
@@ -69,7 +77,7 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	organization, err := d.client.GetOrganization()
+	organization, err := d.client.GetOrganization(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to get organization for provided access token.",
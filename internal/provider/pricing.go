@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func getInstancePriceRequest(ctx context.Context, region types.String, machineImage types.String, cpu types.String, memory types.String, storage types.Int64, replicas types.Int64, volumes []client.PersistentStorage, gpu types.Object, scalable bool) (client.InstancePriceRequest, bool) {
+	if region.IsUnknown() || storage.IsUnknown() || replicas.IsUnknown() {
+		return client.InstancePriceRequest{}, false
+	}
+
+	// GPU conversion errors are reported by validateInstanceGpu, the estimate is only skipped.
+	gpuSpecs, ok, _ := getGpuSpecs(ctx, gpu)
+	if !ok {
+		return client.InstancePriceRequest{}, false
+	}
+
+	request := client.InstancePriceRequest{
+		Region:        region.ValueString(),
+		InstanceCount: int(replicas.ValueInt64()),
+		Scalable:      scalable,
+		CustomInstanceSpecs: client.CustomInstanceSpecs{
+			Storage: fmt.Sprintf("%dGi", int(storage.ValueInt64())),
+			Gpu:     gpuSpecs,
+		},
+	}
+	request.CustomInstanceSpecs.SetVolumes(volumes)
+
+	if !machineImage.IsUnknown() && machineImage.ValueString() != "" && machineImage.ValueString() != "Custom Plan" {
+		request.AkashMachineImageName = machineImage.ValueString()
+		return request, true
+	}
+
+	if cpu.IsUnknown() || cpu.IsNull() || memory.IsUnknown() || memory.IsNull() {
+		return client.InstancePriceRequest{}, false
+	}
+
+	request.CustomInstanceSpecs.CPU = formatCpu(cpu.ValueString())
+	request.CustomInstanceSpecs.Memory = formatMemory(memory.ValueString())
+
+	return request, true
+}
+
+func estimateInstanceCost(ctx context.Context, api *client.SpheronApi, request client.InstancePriceRequest) (types.Float64, types.Float64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	price, err := api.GetInstancePrice(ctx, request)
+	if err != nil {
+		diags.AddWarning("Unable to estimate instance cost.", err.Error())
+		return types.Float64Unknown(), types.Float64Unknown(), diags
+	}
+
+	return types.Float64Value(price.PricePerHour), types.Float64Value(price.PricePerMonth), diags
+}
+
+func float64UnknownAsNull(value types.Float64) types.Float64 {
+	if value.IsUnknown() {
+		return types.Float64Null()
+	}
+
+	return value
+}
+
+func addFloat64Values(a types.Float64, b types.Float64) types.Float64 {
+	if a.IsUnknown() || b.IsUnknown() {
+		return types.Float64Unknown()
+	}
+
+	return types.Float64Value(a.ValueFloat64() + b.ValueFloat64())
+}
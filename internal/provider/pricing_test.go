@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGetInstancePriceRequest(t *testing.T) {
+	ctx := context.Background()
+	request, ok := getInstancePriceRequest(ctx, types.StringValue("us-east"), types.StringUnknown(), types.StringValue("2"), types.StringValue("4"), types.Int64Value(10), types.Int64Value(3), nil, types.ObjectNull(gpuAttrTypes), true)
+	if !ok {
+		t.Fatal("expected price request for custom spec")
+	}
+	expected := client.InstancePriceRequest{
+		Region:              "us-east",
+		InstanceCount:       3,
+		Scalable:            true,
+		CustomInstanceSpecs: client.CustomInstanceSpecs{CPU: "2", Memory: "4Gi", Storage: "10Gi"},
+	}
+	if !reflect.DeepEqual(request, expected) {
+		t.Errorf("expected %+v, got %+v", expected, request)
+	}
+
+	request, ok = getInstancePriceRequest(ctx, types.StringValue("any"), types.StringValue("Ventus Small"), types.StringUnknown(), types.StringUnknown(), types.Int64Value(10), types.Int64Value(1), nil, types.ObjectNull(gpuAttrTypes), false)
+	if !ok || request.AkashMachineImageName != "Ventus Small" || request.CustomInstanceSpecs.CPU != "" {
+		t.Errorf("expected machine image price request, got %+v (%v)", request, ok)
+	}
+
+	if _, ok := getInstancePriceRequest(ctx, types.StringUnknown(), types.StringValue("Ventus Small"), types.StringNull(), types.StringNull(), types.Int64Value(10), types.Int64Value(1), nil, types.ObjectNull(gpuAttrTypes), false); ok {
+		t.Error("expected no price request for unknown region")
+	}
+
+	if _, ok := getInstancePriceRequest(ctx, types.StringValue("any"), types.StringUnknown(), types.StringUnknown(), types.StringUnknown(), types.Int64Value(10), types.Int64Value(1), nil, types.ObjectNull(gpuAttrTypes), false); ok {
+		t.Error("expected no price request for unknown spec")
+	}
+
+	gpu := types.ObjectValueMust(gpuAttrTypes, map[string]attr.Value{
+		"count":  types.Int64Value(2),
+		"vendor": types.StringUnknown(),
+		"model":  types.StringValue("a100"),
+		"vram":   types.Int64Value(80),
+	})
+	request, ok = getInstancePriceRequest(ctx, types.StringValue("us-east"), types.StringNull(), types.StringValue("8"), types.StringValue("32"), types.Int64Value(10), types.Int64Value(1), nil, gpu, false)
+	expectedGpu := &client.GpuSpecs{Count: 2, Model: "a100", Vram: "80Gi"}
+	if !ok || !reflect.DeepEqual(request.CustomInstanceSpecs.Gpu, expectedGpu) {
+		t.Errorf("expected gpu price request with %+v, got %+v (%v)", expectedGpu, request.CustomInstanceSpecs.Gpu, ok)
+	}
+}
@@ -2,15 +2,17 @@ package provider
 
 import (
 	"context"
-	"os"
 
 	"terraform-provider-spheron/internal/client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -21,8 +23,20 @@ type SpheronProvider struct {
 	version string
 }
 
+// providerData is shared with resources and data sources. client and token are nil while the credentials are unknown.
+type providerData struct {
+	client *client.SpheronApi
+	budget *spendBudget
+	token  *tokenCheck
+}
+
 type SpheronProviderModel struct {
-	Token types.String `tfsdk:"token"`
+	Token          types.String  `tfsdk:"token"`
+	TokenCommand   types.List    `tfsdk:"token_command"`
+	Profile        types.String  `tfsdk:"profile"`
+	ApiUrl         types.String  `tfsdk:"api_url"`
+	MaxHourlySpend types.Float64 `tfsdk:"max_hourly_spend"`
+	MaxInstances   types.Int64   `tfsdk:"max_instances"`
 }
 
 func (p *SpheronProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -36,6 +50,37 @@ func (p *SpheronProvider) Schema(ctx context.Context, req provider.SchemaRequest
 			"token": schema.StringAttribute{
 				MarkdownDescription: "Spheron access token. If left empty provide SPHERON_TOKEN env variable.",
 				Optional:            true,
+				Sensitive:           true,
+			},
+			"token_command": schema.ListAttribute{
+				MarkdownDescription: "Command and arguments of a credential helper that prints the Spheron access token to stdout. Used when token is not set.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Name of the profile in the credentials file to read the token from. If left empty SPHERON_PROFILE env variable is used, defaulting to default.",
+				Optional:            true,
+			},
+			"api_url": schema.StringAttribute{
+				MarkdownDescription: "Spheron API URL. If left empty SPHERON_API_URL env variable is used, defaulting to https://api-v2.spheron.network.",
+				Optional:            true,
+			},
+			"max_hourly_spend": schema.Float64Attribute{
+				MarkdownDescription: "Maximum estimated spend in USD per hour across the active instances of the organization and the instances planned by this provider configuration.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"max_instances": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of active instances in the organization, including the instances planned by this provider configuration. Every instance counts once regardless of its replicas; use max_hourly_spend to limit replicas.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 		},
 		Blocks:              map[string]schema.Block{},
@@ -53,28 +98,40 @@ func (p *SpheronProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	if config.Token.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("token"),
-			"Unknown Spheron access token",
-			"The provider cannot create the Spheron API client as there is an unknown token value for the Spheron API token. "+
-				"Either set the value directly in the provider, or use the SPHERON_TOKEN environment variable.",
-		)
+	budget := newSpendBudget(config.MaxHourlySpend, config.MaxInstances)
+
+	if config.Token.IsUnknown() || config.TokenCommand.IsUnknown() || config.Profile.IsUnknown() {
+		tflog.Info(ctx, "Spheron credentials are unknown, deferring client configuration")
+
+		resp.DataSourceData = &providerData{budget: budget}
+		resp.ResourceData = &providerData{budget: budget}
+		return
 	}
 
-	if resp.Diagnostics.HasError() {
+	token, apiUrl, err := resolveCredentials(ctx, config)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read Spheron access token",
+			err.Error(),
+		)
 		return
 	}
 
-	token := os.Getenv("SPHERON_TOKEN")
+	ctx = maskSensitiveValues(ctx, token)
 
-	if !config.Token.IsNull() {
-		token = config.Token.ValueString()
+	if token == "" {
+		resp.Diagnostics.AddError(
+			"Missing Spheron access token",
+			"The provider cannot create the Spheron API client as there is no Spheron API token. "+
+				"Set the token or token_command attribute, the SPHERON_TOKEN environment variable, "+
+				"or add a profile to the credentials file.",
+		)
+		return
 	}
 
 	tflog.Debug(ctx, "Creating Spheron client")
 
-	spheronApi, err := client.NewSpheronApi(token)
+	spheronApi, err := client.NewSpheronApi(token, apiUrl)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -86,20 +143,16 @@ func (p *SpheronProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	_, err = spheronApi.GetOrganization()
+	budget.api = spheronApi
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Spheron API Client",
-			"An unexpected error occurred when creating the Spheron API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Spheron Client Error: "+err.Error(),
-		)
-		return
+	data := &providerData{
+		client: spheronApi,
+		budget: budget,
+		token:  newTokenCheck(spheronApi),
 	}
 
-	resp.DataSourceData = spheronApi
-	resp.ResourceData = spheronApi
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 func (p *SpheronProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -107,12 +160,18 @@ func (p *SpheronProvider) Resources(ctx context.Context) []func() resource.Resou
 		NewInstanceResource,
 		NewDomainResource,
 		NewMarketplaceInstanceResource,
+		NewDeploymentResource,
 	}
 }
 
 func (p *SpheronProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewOrganizationDataSource,
+		NewRegionsDataSource,
+		NewInstanceEscrowDataSource,
+		NewTokenDataSource,
+		NewComposeDataSource,
+		NewGpuModelsDataSource,
 	}
 }
 
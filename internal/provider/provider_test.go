@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"testing"
+
+	"terraform-provider-spheron/internal/client/fake"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+const testAccProviderConfig = `
+provider "spheron" {}
+`
+
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"spheron": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccFakeServer starts an in-memory Spheron API and points the provider at it.
+func testAccFakeServer(t *testing.T) *fake.Server {
+	t.Helper()
+
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+
+	t.Setenv("SPHERON_API_URL", server.URL)
+	t.Setenv("SPHERON_TOKEN", server.Token)
+
+	return server
+}
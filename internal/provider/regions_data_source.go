@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &RegionsDataSource{}
+
+func NewRegionsDataSource() datasource.DataSource {
+	return &RegionsDataSource{}
+}
+
+type RegionsDataSource struct {
+	client *client.SpheronApi
+	token  *tokenCheck
+}
+
+type RegionsDataSourceModel struct {
+	ID      types.String  `tfsdk:"id"`
+	Regions []RegionModel `tfsdk:"regions"`
+}
+
+type RegionModel struct {
+	Name                types.String  `tfsdk:"name"`
+	AvailableCpu        types.Float64 `tfsdk:"available_cpu"`
+	AvailableMemory     types.Float64 `tfsdk:"available_memory"`
+	AvailableStorage    types.Float64 `tfsdk:"available_storage"`
+	CpuPricePerHour     types.Float64 `tfsdk:"cpu_price_per_hour"`
+	MemoryPricePerHour  types.Float64 `tfsdk:"memory_price_per_hour"`
+	StoragePricePerHour types.Float64 `tfsdk:"storage_price_per_hour"`
+}
+
+func (d *RegionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regions"
+}
+
+func (d *RegionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Regions data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier.",
+				Computed:            true,
+			},
+			"regions": schema.ListNestedAttribute{
+				MarkdownDescription: "Regions available for deploying instances.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Region name.",
+							Computed:            true,
+						},
+						"available_cpu": schema.Float64Attribute{
+							MarkdownDescription: "CPU currently available in the region.",
+							Computed:            true,
+						},
+						"available_memory": schema.Float64Attribute{
+							MarkdownDescription: "Memory in GB currently available in the region.",
+							Computed:            true,
+						},
+						"available_storage": schema.Float64Attribute{
+							MarkdownDescription: "Storage in GB currently available in the region.",
+							Computed:            true,
+						},
+						"cpu_price_per_hour": schema.Float64Attribute{
+							MarkdownDescription: "Price in USD per CPU per hour.",
+							Computed:            true,
+						},
+						"memory_price_per_hour": schema.Float64Attribute{
+							MarkdownDescription: "Price in USD per GB of memory per hour.",
+							Computed:            true,
+						},
+						"storage_price_per_hour": schema.Float64Attribute{
+							MarkdownDescription: "Price in USD per GB of storage per hour.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RegionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare Spheron API client.")
+		return
+	}
+	d.client = data.client
+	d.token = data.token
+}
+
+func (d *RegionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read regions data source.")
+
+	resp.Diagnostics.Append(d.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	regions, err := d.client.GetRegions(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to get available regions.",
+			err.Error(),
+		)
+		return
+	}
+
+	state := RegionsDataSourceModel{
+		ID:      types.StringValue("regions"),
+		Regions: mapClientRegionsToRegions(regions),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading regions data source", map[string]any{"success": true})
+}
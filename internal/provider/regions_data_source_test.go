@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRegionsDataSource(t *testing.T) {
+	testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+data "spheron_regions" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.#", "4"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.0.name", "us-east"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.0.available_cpu", "64"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.0.available_memory", "256"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.0.available_storage", "4096"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.0.cpu_price_per_hour", "0.012"),
+					resource.TestCheckResourceAttr("data.spheron_regions.test", "regions.3.name", "eu-west"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+const serviceDiscoveryEnvPrefix = "SPHERON_SERVICE_"
+
+// getServiceDiscoveryEnvs returns the environment variables with the host and exposed ports of the
+// services a service depends on.
+func getServiceDiscoveryEnvs(dependsOn []string, deployed map[string]deployedService) []client.Env {
+	var envs []client.Env
+	for _, name := range dependsOn {
+		service, ok := deployed[name]
+		if !ok {
+			continue
+		}
+
+		prefix := getServiceDiscoveryEnvPrefix(name)
+
+		envs = append(envs, client.Env{Value: fmt.Sprintf("%s_HOST=%s", prefix, service.host)})
+		for _, port := range service.ports {
+			envs = append(envs, client.Env{Value: fmt.Sprintf("%s_PORT_%d=%d", prefix, port.ContainerPort, port.ExposedPort)})
+		}
+	}
+
+	return envs
+}
+
+func getServiceDiscoveryEnvPrefix(name string) string {
+	return serviceDiscoveryEnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// isServiceDiscoveryEnv reports whether key is one of the environment variables the provider adds for the
+// services in dependsOn.
+func isServiceDiscoveryEnv(key string, dependsOn []string) bool {
+	for _, name := range dependsOn {
+		prefix := getServiceDiscoveryEnvPrefix(name)
+		if key == prefix+"_HOST" {
+			return true
+		}
+
+		if strings.HasPrefix(key, prefix+"_PORT_") {
+			if _, err := strconv.Atoi(strings.TrimPrefix(key, prefix+"_PORT_")); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// filterServiceDiscoveryEnvs removes the environment variables the provider added for the services in
+// dependsOn, so they are not read into env.
+func filterServiceDiscoveryEnvs(envs []client.Env, dependsOn []string) []client.Env {
+	filtered := make([]client.Env, 0, len(envs))
+	for _, env := range envs {
+		key, _ := splitClientEnv(env.Value)
+		if !env.IsSecret && isServiceDiscoveryEnv(key, dependsOn) {
+			continue
+		}
+		filtered = append(filtered, env)
+	}
+
+	return filtered
+}
+
+// validateServiceDiscoveryEnvs rejects environment variables that would be overwritten by the variables the
+// provider adds for the service dependencies.
+func validateServiceDiscoveryEnvs(services []DeploymentServiceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for i, service := range services {
+		for _, attribute := range []string{"env", "env_secret"} {
+			envs := service.Env
+			if attribute == "env_secret" {
+				envs = service.EnvSecret
+			}
+
+			for _, env := range envs {
+				if env.Key.IsUnknown() || !isServiceDiscoveryEnv(env.Key.ValueString(), service.DependsOn) {
+					continue
+				}
+
+				diags.AddAttributeError(
+					path.Root("service").AtListIndex(i).AtName(attribute),
+					"Invalid environment variable.",
+					fmt.Sprintf("Environment variable %s of service %s is set by the provider for its dependencies.", env.Key.ValueString(), service.Name.ValueString()),
+				)
+			}
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestServiceDiscoveryEnvs(t *testing.T) {
+	deployed := map[string]deployedService{
+		"redis-cache": {host: "provider.example.com", ports: []client.Port{{ContainerPort: 6379, ExposedPort: 31234}}},
+	}
+
+	envs := getServiceDiscoveryEnvs([]string{"redis-cache"}, deployed)
+	expected := []client.Env{
+		{Value: "SPHERON_SERVICE_REDIS_CACHE_HOST=provider.example.com"},
+		{Value: "SPHERON_SERVICE_REDIS_CACHE_PORT_6379=31234"},
+	}
+	if !reflect.DeepEqual(envs, expected) {
+		t.Errorf("expected %v, got %v", expected, envs)
+	}
+
+	all := append([]client.Env{{Value: "MODE=web"}, {Value: "SPHERON_SERVICE_TOKEN=secret", IsSecret: true}, {Value: "SPHERON_SERVICE_NAME=web"}, {Value: "SPHERON_SERVICE_REDIS_CACHE_PORT_HTTP=80"}}, envs...)
+	filtered := filterServiceDiscoveryEnvs(all, []string{"redis-cache"})
+	if !reflect.DeepEqual(filtered, all[:4]) {
+		t.Errorf("expected only discovery envs to be filtered, got %v", filtered)
+	}
+
+	if filtered := filterServiceDiscoveryEnvs(envs, nil); !reflect.DeepEqual(filtered, envs) {
+		t.Errorf("expected envs of services without dependencies to be kept, got %v", filtered)
+	}
+}
+
+func TestValidateServiceDiscoveryEnvs(t *testing.T) {
+	services := []DeploymentServiceModel{
+		{
+			Name:      types.StringValue("web"),
+			Env:       []Env{{Key: types.StringValue("SPHERON_SERVICE_NAME"), Value: types.StringValue("web")}},
+			EnvSecret: []Env{{Key: types.StringValue("SPHERON_SERVICE_REDIS_HOST"), Value: types.StringValue("redis.example.com")}},
+			DependsOn: []string{"redis"},
+		},
+		{
+			Name: types.StringValue("redis"),
+			Env:  []Env{{Key: types.StringValue("SPHERON_SERVICE_REDIS_HOST"), Value: types.StringValue("localhost")}},
+		},
+	}
+
+	diags := validateServiceDiscoveryEnvs(services)
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected one error, got %v", diags)
+	}
+	if !strings.Contains(diags.Errors()[0].Detail(), "SPHERON_SERVICE_REDIS_HOST of service web") {
+		t.Errorf("expected error for the dependency host of web, got %s", diags.Errors()[0].Detail())
+	}
+}
@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var cpuPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?m?$`)
+
+// memoryPattern requires Ki, Mi and Ti, or their decimal spelling, since a bare m reads like millibytes.
+var memoryPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([kKmMtT][iIbB]|[gG][iIbB]?|[bB])?$`)
+
+// positiveSize validates that a CPU or memory value is larger than zero.
+type positiveSize struct {
+	parse func(string) (float64, error)
+}
+
+func (v positiveSize) Description(ctx context.Context) string {
+	return "value must be larger than zero"
+}
+
+func (v positiveSize) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v positiveSize) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if size, err := v.parse(req.ConfigValue.ValueString()); err == nil && size <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid size.", fmt.Sprintf("Attribute %s %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()))
+	}
+}
+
+// parseCpu parses CPU in cores, like 0.5, or in millicores, like 500m.
+func parseCpu(value string) (float64, error) {
+	number := strings.TrimSuffix(strings.TrimSpace(value), "m")
+
+	cores, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid CPU value %s.", value)
+	}
+
+	if number != strings.TrimSpace(value) {
+		return cores / 1000, nil
+	}
+	return cores, nil
+}
+
+// parseMemory parses memory in GB, like 2, or with a unit, like 512Mi or 1.5Gi.
+func parseMemory(value string) (float64, error) {
+	if gb, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+		return gb, nil
+	}
+	if !memoryPattern.MatchString(strings.TrimSpace(value)) {
+		return 0, fmt.Errorf("Invalid memory value %s.", value)
+	}
+
+	return parseMemoryGB(value)
+}
+
+var memoryUnits = map[string]float64{
+	"":   1.0 / (1024 * 1024 * 1024),
+	"b":  1.0 / (1024 * 1024 * 1024),
+	"k":  1.0 / (1024 * 1024),
+	"kb": 1.0 / (1024 * 1024),
+	"ki": 1.0 / (1024 * 1024),
+	"m":  1.0 / 1024,
+	"mb": 1.0 / 1024,
+	"mi": 1.0 / 1024,
+	"g":  1,
+	"gb": 1,
+	"gi": 1,
+	"t":  1024,
+	"tb": 1024,
+	"ti": 1024,
+}
+
+// parseMemoryGB parses sizes like 512Mi, 512M, 1.5Gi or 1g. Decimal and binary units are both read as
+// binary, which is how Spheron allocates memory and storage.
+func parseMemoryGB(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+
+	i := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(value)
+	}
+
+	number, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid size %s.", value)
+	}
+
+	factor, ok := memoryUnits[strings.ToLower(value[i:])]
+	if !ok {
+		return 0, fmt.Errorf("Invalid size %s. Unknown unit %s.", value, value[i:])
+	}
+
+	return number * factor, nil
+}
+
+// formatCpu formats CPU in cores, the unit expected by the Spheron API.
+func formatCpu(value string) string {
+	cores, err := parseCpu(value)
+	if err != nil {
+		return value
+	}
+
+	return strconv.FormatFloat(cores, 'f', -1, 64)
+}
+
+// formatMemory formats memory in Gi, the unit expected by the Spheron API.
+func formatMemory(value string) string {
+	gb, err := parseMemory(value)
+	if err != nil {
+		return value
+	}
+
+	return strconv.FormatFloat(gb, 'f', -1, 64) + "Gi"
+}
+
+// isSameSize reports whether two CPU or memory values are equal once parsed, like 1, 1.0 and 1000m.
+func isSameSize(parse func(string) (float64, error), a string, b string) bool {
+	x, errX := parse(a)
+	y, errY := parse(b)
+
+	return errX == nil && errY == nil && math.Abs(x-y) < 1e-9
+}
+
+// getCpuValue maps the agreed CPU to state, keeping the prior value when it is the same size so that the
+// configured spelling doesn't show up as a diff.
+func getCpuValue(prior types.String, cpu float32) types.String {
+	value := fmt.Sprint(cpu)
+	if !prior.IsNull() && !prior.IsUnknown() && isSameSize(parseCpu, prior.ValueString(), value) {
+		return prior
+	}
+
+	return types.StringValue(value)
+}
+
+// getMemoryValue maps the agreed memory to state in GB, keeping the prior value when it is the same size.
+func getMemoryValue(prior types.String, memory string) types.String {
+	value := RemoveGiSuffix(memory)
+	if gb, err := parseMemory(memory); err == nil {
+		value = strconv.FormatFloat(gb, 'f', -1, 64)
+	}
+
+	if !prior.IsNull() && !prior.IsUnknown() && isSameSize(parseMemory, prior.ValueString(), value) {
+		return prior
+	}
+
+	return types.StringValue(value)
+}
+
+func requiresReplaceIfCpuChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsUnknown() && !isSameSize(parseCpu, req.PlanValue.ValueString(), req.StateValue.ValueString())
+}
+
+func requiresReplaceIfMemoryChanged(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsUnknown() && !isSameSize(parseMemory, req.PlanValue.ValueString(), req.StateValue.ValueString())
+}
+
+// validateInstanceSize checks the planned CPU and memory of a replica against the limits of the Spheron API.
+func validateInstanceSize(ctx context.Context, api *client.SpheronApi, cpu types.String, memory types.String, cpuPath path.Path, memoryPath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cores, cpuErr := parseCpu(cpu.ValueString())
+	gb, memoryErr := parseMemory(memory.ValueString())
+	if cpu.IsUnknown() || memory.IsUnknown() || cpuErr != nil || memoryErr != nil {
+		return diags
+	}
+
+	limits, err := api.GetInstanceLimits(ctx)
+	if err != nil {
+		diags.AddAttributeWarning(cpuPath, "Unable to validate instance size.", err.Error())
+		return diags
+	}
+
+	if cores < limits.MinCpu || cores > limits.MaxCpu {
+		diags.AddAttributeError(cpuPath, "Invalid CPU.", fmt.Sprintf("CPU %s is outside of the allowed range of %g to %g cores.", cpu.ValueString(), limits.MinCpu, limits.MaxCpu))
+	}
+	if gb < limits.MinMemory || gb > limits.MaxMemory {
+		diags.AddAttributeError(memoryPath, "Invalid memory.", fmt.Sprintf("Memory %s is outside of the allowed range of %gGi to %gGi.", memory.ValueString(), limits.MinMemory, limits.MaxMemory))
+	}
+
+	return diags
+}
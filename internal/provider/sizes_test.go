@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseInstanceSize(t *testing.T) {
+	cpuCases := map[string]float64{"1": 1, "0.5": 0.5, "1.0": 1, "2000m": 2, "250m": 0.25}
+	for value, expected := range cpuCases {
+		if actual, err := parseCpu(value); err != nil || actual != expected {
+			t.Errorf("expected cpu %s to be %g, got %g, %v", value, expected, actual, err)
+		}
+		if !cpuPattern.MatchString(value) {
+			t.Errorf("expected cpu %s to match the cpu pattern", value)
+		}
+	}
+
+	memoryCases := map[string]float64{"2": 2, "0.5": 0.5, "512Mi": 0.5, "1.5Gi": 1.5, "2G": 2, "1Ti": 1024}
+	for value, expected := range memoryCases {
+		if actual, err := parseMemory(value); err != nil || actual != expected {
+			t.Errorf("expected memory %s to be %g, got %g, %v", value, expected, actual, err)
+		}
+		if !memoryPattern.MatchString(value) {
+			t.Errorf("expected memory %s to match the memory pattern", value)
+		}
+	}
+
+	for _, value := range []string{"", "two", "1.5 cores", "-1", "1e3"} {
+		if cpuPattern.MatchString(value) {
+			t.Errorf("expected cpu %q not to match the cpu pattern", value)
+		}
+	}
+	for _, value := range []string{"", "1Q", "1.5 GB", "-1", "Gi", "512m", "512k", "1t"} {
+		if memoryPattern.MatchString(value) {
+			t.Errorf("expected memory %q not to match the memory pattern", value)
+		}
+	}
+	for _, value := range []string{"512m", "512k", "1t"} {
+		if _, err := parseMemory(value); err == nil {
+			t.Errorf("expected memory %q not to be parsed", value)
+		}
+	}
+
+	if actual := formatMemory("512Mi"); actual != "0.5Gi" {
+		t.Errorf("expected 512Mi to be formatted as 0.5Gi, got %s", actual)
+	}
+	if actual := formatCpu("1500m"); actual != "1.5" {
+		t.Errorf("expected 1500m to be formatted as 1.5, got %s", actual)
+	}
+}
+
+func TestPositiveSize(t *testing.T) {
+	testCases := map[string]struct {
+		validator positiveSize
+		value     types.String
+		wantErr   bool
+	}{
+		"cpu":          {validator: positiveSize{parse: parseCpu}, value: types.StringValue("0.5")},
+		"zero cpu":     {validator: positiveSize{parse: parseCpu}, value: types.StringValue("0"), wantErr: true},
+		"zero millis":  {validator: positiveSize{parse: parseCpu}, value: types.StringValue("0m"), wantErr: true},
+		"memory":       {validator: positiveSize{parse: parseMemory}, value: types.StringValue("512Mi")},
+		"zero memory":  {validator: positiveSize{parse: parseMemory}, value: types.StringValue("0Gi"), wantErr: true},
+		"invalid":      {validator: positiveSize{parse: parseCpu}, value: types.StringValue("two")},
+		"unknown":      {validator: positiveSize{parse: parseCpu}, value: types.StringUnknown()},
+		"unconfigured": {validator: positiveSize{parse: parseCpu}, value: types.StringNull()},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var resp validator.StringResponse
+			tc.validator.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("cpu"), ConfigValue: tc.value}, &resp)
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Errorf("expected error %t, got %v", tc.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestGetInstanceSizeValue(t *testing.T) {
+	testCases := []struct {
+		prior    types.String
+		cpu      float32
+		memory   string
+		expected string
+	}{
+		{prior: types.StringNull(), cpu: 0.5, memory: "0.5Gi", expected: "0.5"},
+		{prior: types.StringValue("1.0"), cpu: 1, memory: "1Gi", expected: "1.0"},
+		{prior: types.StringValue("2"), cpu: 4, memory: "4Gi", expected: "4"},
+	}
+
+	for _, tc := range testCases {
+		if actual := getCpuValue(tc.prior, tc.cpu); actual.ValueString() != tc.expected {
+			t.Errorf("getCpuValue(%s, %g): expected %s, got %s", tc.prior, tc.cpu, tc.expected, actual)
+		}
+		if actual := getMemoryValue(tc.prior, tc.memory); actual.ValueString() != tc.expected {
+			t.Errorf("getMemoryValue(%s, %s): expected %s, got %s", tc.prior, tc.memory, tc.expected, actual)
+		}
+	}
+
+	if actual := getCpuValue(types.StringValue("500m"), 0.5); actual.ValueString() != "500m" {
+		t.Errorf("expected 500m to be kept, got %s", actual)
+	}
+	if actual := getMemoryValue(types.StringValue("512Mi"), "0.5Gi"); actual.ValueString() != "512Mi" {
+		t.Errorf("expected 512Mi to be kept, got %s", actual)
+	}
+	if actual := getMemoryValue(types.StringNull(), "512Mi"); actual.ValueString() != "0.5" {
+		t.Errorf("expected 512Mi to be read as 0.5, got %s", actual)
+	}
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const deployTokenPermission = "deploy"
+
+// tokenCheck validates the access token on first use, so the provider can be configured without reaching the API.
+// The result is kept once the token scope is known or the API rejected the token, other errors are retried.
+type tokenCheck struct {
+	api *client.SpheronApi
+
+	mu    sync.Mutex
+	done  bool
+	scope client.TokenScope
+	diags diag.Diagnostics
+}
+
+func newTokenCheck(api *client.SpheronApi) *tokenCheck {
+	return &tokenCheck{api: api}
+}
+
+func (c *tokenCheck) validate(ctx context.Context) diag.Diagnostics {
+	if c == nil {
+		var diags diag.Diagnostics
+		diags.AddError(
+			"Unconfigured Spheron API client",
+			"The provider was not configured because the Spheron access token is unknown. "+
+				"Resources and data sources can be read once the token is known.",
+		)
+		return diags
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done {
+		return c.diags
+	}
+
+	scope, err := c.api.GetTokenScope(ctx)
+	if err != nil {
+		if !isTokenRejected(err) {
+			var diags diag.Diagnostics
+			diags.Append(getTokenScopeErrorDiagnostic(err))
+			return diags
+		}
+
+		c.done = true
+		c.diags.Append(getTokenScopeErrorDiagnostic(err))
+		return c.diags
+	}
+
+	c.done = true
+	c.scope = scope
+	c.diags.Append(validateTokenScope(scope, time.Now())...)
+
+	return c.diags
+}
+
+func (c *tokenCheck) checkDeployPermission(ctx context.Context) diag.Diagnostics {
+	diags := c.validate(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	return checkDeployPermission(c.scope)
+}
+
+func isTokenRejected(err error) bool {
+	var apiErr *client.ApiError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+func getTokenScopeErrorDiagnostic(err error) diag.Diagnostic {
+	var apiErr *client.ApiError
+	if !errors.As(err, &apiErr) {
+		return diag.NewErrorDiagnostic(
+			"Unable to reach Spheron API",
+			"The provider could not connect to the Spheron API to validate the access token. "+
+				"Check the api_url and your network connection.\n\n"+
+				"Spheron Client Error: "+err.Error(),
+		)
+	}
+
+	if apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden {
+		if strings.Contains(strings.ToLower(apiErr.Message), "expired") {
+			return diag.NewErrorDiagnostic(
+				"Expired Spheron access token",
+				"The Spheron access token has expired. Create a new token in the Spheron console.",
+			)
+		}
+
+		return diag.NewErrorDiagnostic(
+			"Invalid Spheron access token",
+			"The Spheron API rejected the access token. Check that the token is correct and has not been revoked.\n\n"+
+				"Spheron Client Error: "+apiErr.Error(),
+		)
+	}
+
+	return diag.NewErrorDiagnostic(
+		"Unable to validate Spheron access token",
+		fmt.Sprintf("The Spheron API responded with status %d.\n\nSpheron Client Error: %s", apiErr.StatusCode, apiErr.Error()),
+	)
+}
+
+func validateTokenScope(scope client.TokenScope, now time.Time) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if scope.ExpiresAt != nil && scope.ExpiresAt.Before(now) {
+		diags.AddError(
+			"Expired Spheron access token",
+			fmt.Sprintf("The Spheron access token expired at %s. Create a new token in the Spheron console.", scope.ExpiresAt.UTC().Format(time.RFC3339)),
+		)
+		return diags
+	}
+
+	if len(scope.Organizations) != 1 {
+		diags.AddError(
+			"Unsupported Spheron access token",
+			"Unsupported token! Please use a single scope token.",
+		)
+	}
+
+	return diags
+}
+
+func hasDeployPermission(scope client.TokenScope) bool {
+	if len(scope.Permissions) == 0 {
+		return true
+	}
+
+	for _, permission := range scope.Permissions {
+		if permission == deployTokenPermission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkDeployPermission(scope client.TokenScope) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !hasDeployPermission(scope) {
+		diags.AddError(
+			"Spheron access token is missing deploy permission",
+			fmt.Sprintf("The access token only has the %s permissions. Use a token with the %s permission to manage instances.", strings.Join(scope.Permissions, ", "), deployTokenPermission),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &TokenDataSource{}
+
+func NewTokenDataSource() datasource.DataSource {
+	return &TokenDataSource{}
+}
+
+type TokenDataSource struct {
+	client *client.SpheronApi
+	token  *tokenCheck
+}
+
+type TokenDataSourceModel struct {
+	ID            types.String             `tfsdk:"id"`
+	User          TokenUserModel           `tfsdk:"user"`
+	Organizations []TokenOrganizationModel `tfsdk:"organizations"`
+	Permissions   []types.String           `tfsdk:"permissions"`
+	CanDeploy     types.Bool               `tfsdk:"can_deploy"`
+	ExpiresAt     types.String             `tfsdk:"expires_at"`
+}
+
+type TokenUserModel struct {
+	ID       types.String `tfsdk:"id"`
+	Username types.String `tfsdk:"username"`
+	Name     types.String `tfsdk:"name"`
+	Email    types.String `tfsdk:"email"`
+}
+
+type TokenOrganizationModel struct {
+	ID       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Username types.String `tfsdk:"username"`
+}
+
+func (d *TokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token"
+}
+
+func (d *TokenDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Token data source.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Id of the user the token belongs to.",
+				Computed:            true,
+			},
+			"user": schema.SingleNestedAttribute{
+				MarkdownDescription: "User the token belongs to.",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "User identifier.",
+						Computed:            true,
+					},
+					"username": schema.StringAttribute{
+						MarkdownDescription: "User username.",
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "User name.",
+						Computed:            true,
+					},
+					"email": schema.StringAttribute{
+						MarkdownDescription: "User email.",
+						Computed:            true,
+					},
+				},
+			},
+			"organizations": schema.ListNestedAttribute{
+				MarkdownDescription: "Organizations the token has access to.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Organization identifier.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Organization name.",
+							Computed:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "Organization username.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"permissions": schema.ListAttribute{
+				MarkdownDescription: "Permissions granted to the token. Empty when the API doesn't report token permissions.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"can_deploy": schema.BoolAttribute{
+				MarkdownDescription: "Whether the token can be used to deploy instances.",
+				Computed:            true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "Time the token expires, in RFC3339 format. Null for tokens without expiry.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *TokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		tflog.Error(ctx, "Unable to prepare Spheron API client.")
+		return
+	}
+	d.client = data.client
+	d.token = data.token
+}
+
+func (d *TokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	tflog.Debug(ctx, "Preparing to read token data source.")
+
+	resp.Diagnostics.Append(d.token.validate(ctx)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scope := d.token.scope
+
+	state := TokenDataSourceModel{
+		ID: types.StringValue(scope.User.ID),
+		User: TokenUserModel{
+			ID:       types.StringValue(scope.User.ID),
+			Username: types.StringValue(scope.User.Username),
+			Name:     types.StringValue(scope.User.Name),
+			Email:    types.StringValue(scope.User.Email),
+		},
+		Organizations: make([]TokenOrganizationModel, 0, len(scope.Organizations)),
+		Permissions:   make([]types.String, 0, len(scope.Permissions)),
+		CanDeploy:     types.BoolValue(hasDeployPermission(scope)),
+		ExpiresAt:     types.StringNull(),
+	}
+
+	for _, organization := range scope.Organizations {
+		state.Organizations = append(state.Organizations, TokenOrganizationModel{
+			ID:       types.StringValue(organization.ID),
+			Name:     types.StringValue(organization.Name),
+			Username: types.StringValue(organization.Username),
+		})
+	}
+
+	for _, permission := range scope.Permissions {
+		state.Permissions = append(state.Permissions, types.StringValue(permission))
+	}
+
+	if scope.ExpiresAt != nil {
+		state.ExpiresAt = types.StringValue(scope.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	tflog.Debug(ctx, "Finished reading token data source", map[string]any{"success": true})
+}
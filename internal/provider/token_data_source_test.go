@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccTokenDataSource(t *testing.T) {
+	server := testAccFakeServer(t)
+	server.TokenExpiresAt = time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig + `
+data "spheron_token" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.spheron_token.test", "id", "user-1"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "user.username", "terraform"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "user.email", "terraform@spheron.network"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "organizations.#", "1"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "organizations.0.id", "org-1"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "permissions.#", "2"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "permissions.1", "deploy"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "can_deploy", "true"),
+					resource.TestCheckResourceAttr("data.spheron_token.test", "expires_at", "2099-01-01T00:00:00Z"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestGetTokenScopeErrorDiagnostic(t *testing.T) {
+	testCases := map[string]struct {
+		err     error
+		summary string
+	}{
+		"invalid token": {
+			err:     &client.ApiError{StatusCode: 401, Message: "Unauthorized"},
+			summary: "Invalid Spheron access token",
+		},
+		"expired token": {
+			err:     &client.ApiError{StatusCode: 401, Message: "Token expired"},
+			summary: "Expired Spheron access token",
+		},
+		"server error": {
+			err:     &client.ApiError{StatusCode: 500, Message: "Internal server error"},
+			summary: "Unable to validate Spheron access token",
+		},
+		"network error": {
+			err:     &url.Error{Op: "Get", URL: "https://api-v2.spheron.network/v1/api-keys/scope", Err: errors.New("connection refused")},
+			summary: "Unable to reach Spheron API",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := getTokenScopeErrorDiagnostic(tc.err)
+			if got.Summary() != tc.summary {
+				t.Errorf("expected %q, got %q", tc.summary, got.Summary())
+			}
+		})
+	}
+}
+
+func TestValidateTokenScope(t *testing.T) {
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	organizations := []client.TokenOrganization{{ID: "org-1"}}
+
+	testCases := map[string]struct {
+		scope   client.TokenScope
+		summary string
+	}{
+		"valid":             {scope: client.TokenScope{Organizations: organizations, ExpiresAt: &future}},
+		"without expiry":    {scope: client.TokenScope{Organizations: organizations}},
+		"expired":           {scope: client.TokenScope{Organizations: organizations, ExpiresAt: &past}, summary: "Expired Spheron access token"},
+		"multiple orgs":     {scope: client.TokenScope{Organizations: append(organizations, client.TokenOrganization{ID: "org-2"})}, summary: "Unsupported Spheron access token"},
+		"no organizations":  {scope: client.TokenScope{}, summary: "Unsupported Spheron access token"},
+		"read only allowed": {scope: client.TokenScope{Organizations: organizations, Permissions: []string{"read"}}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := validateTokenScope(tc.scope, now)
+			if tc.summary == "" && diags.HasError() {
+				t.Errorf("unexpected error %v", diags)
+			}
+			if tc.summary != "" && (!diags.HasError() || diags.Errors()[0].Summary() != tc.summary) {
+				t.Errorf("expected %q, got %v", tc.summary, diags)
+			}
+		})
+	}
+}
+
+func TestCheckDeployPermission(t *testing.T) {
+	if diags := checkDeployPermission(client.TokenScope{}); diags.HasError() {
+		t.Errorf("expected tokens without reported permissions to be allowed, got %v", diags)
+	}
+	if diags := checkDeployPermission(client.TokenScope{Permissions: []string{"read", "deploy"}}); diags.HasError() {
+		t.Errorf("expected deploy token to be allowed, got %v", diags)
+	}
+	if diags := checkDeployPermission(client.TokenScope{Permissions: []string{"read"}}); !diags.HasError() {
+		t.Error("expected read only token to be rejected")
+	}
+}
+
+func TestAccProvider_token(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { t.Setenv("SPHERON_TOKEN", "invalid-token") },
+				Config:      testAccProviderConfig + `data "spheron_token" "test" {}`,
+				ExpectError: regexp.MustCompile(`Invalid Spheron access token`),
+			},
+			{
+				PreConfig: func() {
+					t.Setenv("SPHERON_TOKEN", server.Token)
+					server.TokenExpiresAt = time.Now().Add(-time.Hour)
+				},
+				Config:      testAccProviderConfig + `data "spheron_token" "test" {}`,
+				ExpectError: regexp.MustCompile(`Expired Spheron access token`),
+			},
+			{
+				PreConfig: func() {
+					server.TokenExpiresAt = time.Time{}
+					t.Setenv("SPHERON_API_URL", "http://127.0.0.1:1")
+				},
+				Config:      testAccProviderConfig + `data "spheron_token" "test" {}`,
+				ExpectError: regexp.MustCompile(`Unable to reach Spheron API`),
+			},
+			{
+				PreConfig: func() {
+					t.Setenv("SPHERON_API_URL", server.URL)
+					server.TokenPermissions = []string{"read"}
+				},
+				Config:      testAccProviderConfig + testAccInstanceResourceConfig("latest"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Spheron access token is missing deploy permission`),
+			},
+		},
+	})
+}
+
+func TestTokenCheckValidate(t *testing.T) {
+	var unconfigured *tokenCheck
+	if diags := unconfigured.validate(context.Background()); !diags.HasError() || diags.Errors()[0].Summary() != "Unconfigured Spheron API client" {
+		t.Errorf("expected unconfigured client error, got %v", diags)
+	}
+}
+
+func TestTokenCheckValidateCaching(t *testing.T) {
+	testCases := map[string]struct {
+		statuses []int
+		summary  []string
+		requests int
+	}{
+		"valid token": {
+			statuses: []int{http.StatusOK},
+			summary:  []string{"", ""},
+			requests: 1,
+		},
+		"rejected token": {
+			statuses: []int{http.StatusUnauthorized},
+			summary:  []string{"Invalid Spheron access token", "Invalid Spheron access token"},
+			requests: 1,
+		},
+		"transient error": {
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			summary:  []string{"Unable to validate Spheron access token", ""},
+			requests: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tc.statuses[requests]
+				requests++
+
+				w.WriteHeader(status)
+				if status != http.StatusOK {
+					_, _ = w.Write([]byte(`{"message":"failed"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"organizations":[{"id":"org-1"}]}`))
+			}))
+			defer server.Close()
+
+			api, err := client.NewSpheronApi("token", server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+
+			check := newTokenCheck(api)
+			for i, summary := range tc.summary {
+				diags := check.validate(context.Background())
+				if summary == "" && diags.HasError() {
+					t.Errorf("validation %d: unexpected error %v", i, diags)
+				}
+				if summary != "" && (!diags.HasError() || diags.Errors()[0].Summary() != summary) {
+					t.Errorf("validation %d: expected %q, got %v", i, summary, diags)
+				}
+			}
+
+			if requests != tc.requests {
+				t.Errorf("expected %d requests, got %d", tc.requests, requests)
+			}
+		})
+	}
+}
+
+func TestTokenCheckValidateUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	api, err := client.NewSpheronApi("token", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	server.Close()
+
+	check := newTokenCheck(api)
+	if diags := check.validate(context.Background()); !diags.HasError() || diags.Errors()[0].Summary() != "Unable to reach Spheron API" {
+		t.Errorf("expected unreachable API error, got %v", diags)
+	}
+	if check.done {
+		t.Errorf("expected connection errors not to be cached")
+	}
+}
+
+func TestAccProvider_unknownToken(t *testing.T) {
+	server := testAccFakeServer(t)
+
+	t.Setenv("SPHERON_TOKEN", "")
+
+	config := fmt.Sprintf(`
+resource "terraform_data" "token" {
+  input = %q
+}
+
+provider "spheron" {
+  token = terraform_data.token.output
+}
+`, server.Token) + testAccInstanceResourceConfig("latest")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("spheron_instance.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("spheron_instance.test", "id"),
+					resource.TestCheckResourceAttr("spheron_instance.test", "tag", "latest"),
+				),
+			},
+		},
+	})
+}
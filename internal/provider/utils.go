@@ -1,14 +1,17 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"sort"
 	"strings"
+
 	"terraform-provider-spheron/internal/client"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 func findComputeMachineID(machines []client.ComputeMachine, name string) (string, error) {
@@ -18,7 +21,7 @@ func findComputeMachineID(machines []client.ComputeMachine, name string) (string
 		}
 	}
 
-	return "", errors.New("ComputeMachine not found with the provided ID")
+	return "", fmt.Errorf("ComputeMachine not found with name: %s", name)
 }
 
 func findMarketplaceAppByName(apps []client.MarketplaceApp, name string) (client.MarketplaceApp, error) {
@@ -44,36 +47,71 @@ func mapMarketplaceEnvs(envList []Env) []client.MarketplaceDeploymentVariable {
 }
 
 func checkRequiredDeploymentVariables(appVariables []client.MarketplaceAppVariable, envList []Env) ([]client.MarketplaceDeploymentVariable, error) {
-	allVariables := make(map[string]client.MarketplaceAppVariable)
-	missingVariables := make(map[string]bool)
-	deploymentVariables := make([]client.MarketplaceDeploymentVariable, 0, len(envList))
+	providedVariables := make(map[string]string, len(envList))
+	validNames := make([]string, 0, len(appVariables))
+	unknownNames := []string{}
+	missingNames := []string{}
 
 	for _, appVar := range appVariables {
-		allVariables[appVar.Name] = appVar
-		missingVariables[appVar.Name] = true
+		validNames = append(validNames, appVar.Name)
 	}
 
 	for _, env := range envList {
-		if appVar, ok := allVariables[env.Key.ValueString()]; ok {
-			marketplaceEnv := client.MarketplaceDeploymentVariable{
-				Value: env.Value.ValueString(),
-				Label: appVar.Label,
-			}
-			deploymentVariables = append(deploymentVariables, marketplaceEnv)
+		providedVariables[env.Key.ValueString()] = env.Value.ValueString()
+
+		if !containsString(validNames, env.Key.ValueString()) {
+			unknownNames = append(unknownNames, env.Key.ValueString())
+		}
+	}
+
+	if len(unknownNames) != 0 {
+		sort.Strings(unknownNames)
 
-			missingVariables[appVar.Name] = false
+		validList := "none"
+		if len(validNames) != 0 {
+			validList = strings.Join(validNames, ", ")
 		}
+
+		return nil, fmt.Errorf("Unknown deployment variables: %s. Valid variable names are: %s", strings.Join(unknownNames, ", "), validList)
 	}
 
-	for varName, isMissing := range missingVariables {
-		if isMissing {
-			return nil, errors.New(fmt.Sprintf("Missing required deployment variable: %s", varName))
+	deploymentVariables := make([]client.MarketplaceDeploymentVariable, 0, len(appVariables))
+
+	for _, appVar := range appVariables {
+		value, ok := providedVariables[appVar.Name]
+		if !ok {
+			value = appVar.DefaultValue
+		}
+
+		if value == "" && !ok {
+			if appVar.Required {
+				missingNames = append(missingNames, appVar.Name)
+			}
+			continue
 		}
+
+		deploymentVariables = append(deploymentVariables, client.MarketplaceDeploymentVariable{
+			Value: value,
+			Label: appVar.Label,
+		})
+	}
+
+	if len(missingNames) != 0 {
+		return nil, fmt.Errorf("Missing required deployment variable: %s", strings.Join(missingNames, ", "))
 	}
 
 	return deploymentVariables, nil
 }
 
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func mapModelPortToPortValue(portList []client.Port) []attr.Value {
 	ports := make([]attr.Value, len(portList))
 	for i, pm := range portList {
@@ -111,8 +149,11 @@ func mapClientEnvsToEnvsValue(clientEnvs []client.Env, isSecret bool) []attr.Val
 			continue
 		}
 
-		split := strings.SplitN(clientEnv.Value, "=", 2)
-		keyString, valueString := split[0], split[1]
+		keyString, valueString := splitClientEnv(clientEnv.Value)
+
+		if keyString == "SPHERON_INSTANCE_ID" {
+			continue
+		}
 
 		portTypes := make(map[string]attr.Type)
 		portValues := make(map[string]attr.Value)
@@ -184,8 +225,7 @@ func mapClientEnvsToEnvs(clientEnvs []client.Env, isSecret bool) []Env {
 			continue
 		}
 
-		split := strings.SplitN(clientEnv.Value, "=", 2)
-		keyString, valueString := split[0], split[1]
+		keyString, valueString := splitClientEnv(clientEnv.Value)
 
 		if keyString == "SPHERON_INSTANCE_ID" {
 			continue
@@ -206,6 +246,67 @@ func mapClientEnvsToEnvs(clientEnvs []client.Env, isSecret bool) []Env {
 	return envList
 }
 
+func getEnvValues(envs []Env) []string {
+	values := make([]string, 0, len(envs))
+	for _, env := range envs {
+		if env.Value.ValueString() != "" {
+			values = append(values, env.Value.ValueString())
+		}
+	}
+
+	return values
+}
+
+func maskSensitiveValues(ctx context.Context, values ...string) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "Authorization", "token")
+
+	secrets := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	ctx = client.WithSensitiveValues(ctx, secrets...)
+	return tflog.MaskLogStrings(ctx, secrets...)
+}
+
+func splitClientEnv(value string) (string, string) {
+	split := strings.SplitN(value, "=", 2)
+	if len(split) == 1 {
+		return split[0], ""
+	}
+
+	return split[0], split[1]
+}
+
+// clientEnvsEqual compares environment variables by key, ignoring their order and the variables set by Spheron.
+func clientEnvsEqual(a []client.Env, b []client.Env) bool {
+	toMap := func(envs []client.Env) map[string]client.Env {
+		values := make(map[string]client.Env, len(envs))
+		for _, env := range envs {
+			key, _ := splitClientEnv(env.Value)
+			if key != "SPHERON_INSTANCE_ID" {
+				values[key] = env
+			}
+		}
+		return values
+	}
+
+	aValues, bValues := toMap(a), toMap(b)
+	if len(aValues) != len(bValues) {
+		return false
+	}
+
+	for key, env := range aValues {
+		if other, ok := bValues[key]; !ok || other != env {
+			return false
+		}
+	}
+
+	return true
+}
+
 func ParseClientPorts(responseString string) ([]client.Port, error) {
 	trimmedString := strings.TrimPrefix(responseString, "data: ")
 
@@ -231,21 +332,23 @@ func ParseClientPorts(responseString string) ([]client.Port, error) {
 
 func isValidDomainType(value string) bool {
 	switch client.DomainTypeEnum(value) {
-	case client.DomainTypeDomain, client.DomainTypeSubdomain:
+	case client.DomainTypeDomain, client.DomainTypeSubdomain, client.DomainTypeWildcard,
+		client.DomainTypeApex, client.DomainTypeEns, client.DomainTypeHns:
 		return true
 	}
 	return false
 }
 
 func getPortFromDeploymentURL(input client.InstanceOrder, urlStr string) (int, error) {
-	if (input.ProtocolData != nil && input.ProtocolData.ProviderHost != "") || input.URLPreview != "" {
+	if input.ClusterInstanceConfiguration != nil && urlStr != "" {
+		providerHost := getOrderProviderHost(input)
+
 		for _, port := range input.ClusterInstanceConfiguration.Ports {
 			if urlStr == input.URLPreview && port.ExposedPort == 80 {
 				return port.ContainerPort, nil
 			}
 
-			expectedURL := fmt.Sprintf("%s:%d", input.ProtocolData.ProviderHost, port.ExposedPort)
-			if urlStr == expectedURL {
+			if providerHost != "" && urlStr == fmt.Sprintf("%s:%d", providerHost, port.ExposedPort) {
 				return port.ContainerPort, nil
 			}
 		}
@@ -254,6 +357,14 @@ func getPortFromDeploymentURL(input client.InstanceOrder, urlStr string) (int, e
 	return 0, fmt.Errorf("no matching port found for the provided URL")
 }
 
+func getOrderProviderHost(input client.InstanceOrder) string {
+	if input.ProtocolData == nil {
+		return ""
+	}
+
+	return input.ProtocolData.ProviderHost
+}
+
 func findDomainByID(domains []client.Domain, id string) (client.Domain, error) {
 	for _, domain := range domains {
 		if domain.ID == id {
@@ -264,15 +375,23 @@ func findDomainByID(domains []client.Domain, id string) (client.Domain, error) {
 }
 
 func getInstanceDeploymentURL(input client.InstanceOrder, desiredPort int) string {
-	if (input.ProtocolData != nil && input.ProtocolData.ProviderHost != "") || input.URLPreview != "" {
-		for _, port := range input.ClusterInstanceConfiguration.Ports {
-			if port.ContainerPort == desiredPort {
-				if port.ExposedPort == 80 && input.URLPreview != "" {
-					return input.URLPreview
-				}
+	if input.ClusterInstanceConfiguration == nil {
+		return ""
+	}
 
-				return fmt.Sprintf("%s:%d", input.ProtocolData.ProviderHost, port.ExposedPort)
+	providerHost := getOrderProviderHost(input)
+
+	for _, port := range input.ClusterInstanceConfiguration.Ports {
+		if port.ContainerPort == desiredPort {
+			if port.ExposedPort == 80 && input.URLPreview != "" {
+				return input.URLPreview
 			}
+
+			if providerHost == "" {
+				return ""
+			}
+
+			return fmt.Sprintf("%s:%d", providerHost, port.ExposedPort)
 		}
 	}
 
@@ -288,7 +407,7 @@ var persistentStorageClassMap = map[string]string{
 func GetPersistentStorageClassEnum(key string) (string, error) {
 	value, ok := persistentStorageClassMap[key]
 	if !ok {
-		return "", fmt.Errorf("Storage class: %s is not supported. Supported values are: HDD, SSD and NVMe.", value)
+		return "", fmt.Errorf("Storage class: %s is not supported. Supported values are: HDD, SSD and NVMe.", key)
 	}
 	return value, nil
 }
@@ -307,8 +426,5 @@ func GetStorageClassFromValue(value string) (string, error) {
 }
 
 func RemoveGiSuffix(input string) string {
-	if len(input) < 2 {
-		return input
-	}
-	return input[:len(input)-2]
+	return strings.TrimSuffix(input, "Gi")
 }
@@ -0,0 +1,432 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func loadOrderFixture(t *testing.T, name string) client.InstanceOrder {
+	t.Helper()
+
+	var response struct {
+		Order client.InstanceOrder `json:"order"`
+	}
+	loadJSONFixture(t, name, &response)
+
+	return response.Order
+}
+
+func loadJSONFixture(t *testing.T, name string, v interface{}) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("unable to read fixture %s: %s", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("unable to unmarshal fixture %s: %s", name, err)
+	}
+}
+
+func TestParseClientPorts(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "deployed_event.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := ParseClientPorts(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []client.Port{
+		{ContainerPort: 8000, ExposedPort: 80},
+		{ContainerPort: 9000, ExposedPort: 31234},
+	}
+	if !reflect.DeepEqual(ports, expected) {
+		t.Errorf("expected %v, got %v", expected, ports)
+	}
+
+	if _, err := ParseClientPorts("data: not json"); err == nil {
+		t.Error("expected error for malformed event data")
+	}
+}
+
+func TestGetInstanceDeploymentURL(t *testing.T) {
+	testCases := map[string]struct {
+		fixture  string
+		port     int
+		expected string
+	}{
+		"url preview for port 80": {
+			fixture:  "order_url_preview.json",
+			port:     8000,
+			expected: "tdqcjbspaqjd3fm3n1g8c5rf3c.ingress.provider.spheron.network",
+		},
+		"provider host for random port": {
+			fixture:  "order_url_preview.json",
+			port:     9000,
+			expected: "provider.spheron.network:31234",
+		},
+		"unknown container port": {
+			fixture:  "order_url_preview.json",
+			port:     1234,
+			expected: "",
+		},
+		"url preview without protocol data": {
+			fixture:  "order_no_protocol_data.json",
+			port:     8080,
+			expected: "f8a1kq3mv5m7b1gq0a7qkvl4bc.ingress.provider.spheron.network",
+		},
+		"random port without protocol data": {
+			fixture:  "order_no_protocol_data.json",
+			port:     5432,
+			expected: "",
+		},
+		"provider host without url preview": {
+			fixture:  "order_provider_host.json",
+			port:     80,
+			expected: "provider.europlots.com:32100",
+		},
+		"order without configuration": {
+			fixture:  "order_pending.json",
+			port:     80,
+			expected: "",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			order := loadOrderFixture(t, testCase.fixture)
+
+			if got := getInstanceDeploymentURL(order, testCase.port); got != testCase.expected {
+				t.Errorf("expected %q, got %q", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetPortFromDeploymentURL(t *testing.T) {
+	testCases := map[string]struct {
+		fixture     string
+		url         string
+		expected    int
+		expectError bool
+	}{
+		"url preview": {
+			fixture:  "order_url_preview.json",
+			url:      "tdqcjbspaqjd3fm3n1g8c5rf3c.ingress.provider.spheron.network",
+			expected: 8000,
+		},
+		"provider host": {
+			fixture:  "order_url_preview.json",
+			url:      "provider.spheron.network:31234",
+			expected: 9000,
+		},
+		"outdated provider host": {
+			fixture:     "order_url_preview.json",
+			url:         "old.provider.network:31234",
+			expectError: true,
+		},
+		"url preview without protocol data": {
+			fixture:  "order_no_protocol_data.json",
+			url:      "f8a1kq3mv5m7b1gq0a7qkvl4bc.ingress.provider.spheron.network",
+			expected: 8080,
+		},
+		"provider host without protocol data": {
+			fixture:     "order_no_protocol_data.json",
+			url:         ":31555",
+			expectError: true,
+		},
+		"empty url": {
+			fixture:     "order_provider_host.json",
+			url:         "",
+			expectError: true,
+		},
+		"order without configuration": {
+			fixture:     "order_pending.json",
+			url:         "provider.spheron.network:31234",
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			order := loadOrderFixture(t, testCase.fixture)
+
+			got, err := getPortFromDeploymentURL(order, testCase.url)
+			if testCase.expectError {
+				if err == nil {
+					t.Errorf("expected error, got port %d", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != testCase.expected {
+				t.Errorf("expected %d, got %d", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredDeploymentVariables(t *testing.T) {
+	var response struct {
+		ClusterTemplates []client.MarketplaceApp `json:"clusterTemplates"`
+	}
+	loadJSONFixture(t, "cluster_templates.json", &response)
+
+	variables := response.ClusterTemplates[0].ServiceData.Variables
+
+	testCases := map[string]struct {
+		env           []Env
+		expected      []client.MarketplaceDeploymentVariable
+		expectedError string
+	}{
+		"all variables set": {
+			env: []Env{
+				{Key: types.StringValue("POSTGRES_PASSWORD"), Value: types.StringValue("secret")},
+				{Key: types.StringValue("POSTGRES_USER"), Value: types.StringValue("admin")},
+				{Key: types.StringValue("POSTGRES_DB"), Value: types.StringValue("db")},
+			},
+			expected: []client.MarketplaceDeploymentVariable{
+				{Label: "Password", Value: "secret"},
+				{Label: "User", Value: "admin"},
+				{Label: "Database", Value: "db"},
+			},
+		},
+		"optional variables fall back to defaults": {
+			env: []Env{
+				{Key: types.StringValue("POSTGRES_PASSWORD"), Value: types.StringValue("secret")},
+				{Key: types.StringValue("POSTGRES_DB"), Value: types.StringValue("db")},
+			},
+			expected: []client.MarketplaceDeploymentVariable{
+				{Label: "Password", Value: "secret"},
+				{Label: "User", Value: "postgres"},
+				{Label: "Database", Value: "db"},
+			},
+		},
+		"missing required variable": {
+			env: []Env{
+				{Key: types.StringValue("POSTGRES_DB"), Value: types.StringValue("db")},
+			},
+			expectedError: "Missing required deployment variable: POSTGRES_PASSWORD",
+		},
+		"unknown variables": {
+			env: []Env{
+				{Key: types.StringValue("POSTGRES_PASSWORD"), Value: types.StringValue("secret")},
+				{Key: types.StringValue("POSTGRES_PASWORD"), Value: types.StringValue("secret")},
+				{Key: types.StringValue("DEBUG"), Value: types.StringValue("true")},
+			},
+			expectedError: "Unknown deployment variables: DEBUG, POSTGRES_PASWORD. Valid variable names are: POSTGRES_PASSWORD, POSTGRES_USER, POSTGRES_DB",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := checkRequiredDeploymentVariables(variables, testCase.env)
+			if testCase.expectedError != "" {
+				if err == nil || err.Error() != testCase.expectedError {
+					t.Fatalf("expected error %q, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestMapClientEnvsToEnvs(t *testing.T) {
+	testCases := map[string]struct {
+		fixture  string
+		isSecret bool
+		expected []Env
+	}{
+		"plain envs skip instance id": {
+			fixture: "order_url_preview.json",
+			expected: []Env{
+				{Key: types.StringValue("PLAIN"), Value: types.StringValue("value")},
+			},
+		},
+		"secret envs keep value separators": {
+			fixture:  "order_url_preview.json",
+			isSecret: true,
+			expected: []Env{
+				{Key: types.StringValue("SECRET"), Value: types.StringValue("hidden=value")},
+			},
+		},
+		"env without separator": {
+			fixture: "order_no_protocol_data.json",
+			expected: []Env{
+				{Key: types.StringValue("POSTGRES_PASSWORD"), Value: types.StringValue("secret")},
+				{Key: types.StringValue("EMPTY"), Value: types.StringValue("")},
+			},
+		},
+		"no envs": {
+			fixture:  "order_provider_host.json",
+			expected: nil,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			order := loadOrderFixture(t, testCase.fixture)
+
+			got := mapClientEnvsToEnvs(order.ClusterInstanceConfiguration.Env, testCase.isSecret)
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Errorf("expected %v, got %v", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestClientEnvsEqual(t *testing.T) {
+	envs := []client.Env{{Value: "A=1"}, {Value: "B=2"}, {Value: "SECRET=x", IsSecret: true}}
+
+	testCases := map[string]struct {
+		other    []client.Env
+		expected bool
+	}{
+		"same order":         {other: []client.Env{{Value: "A=1"}, {Value: "B=2"}, {Value: "SECRET=x", IsSecret: true}}, expected: true},
+		"different order":    {other: []client.Env{{Value: "SECRET=x", IsSecret: true}, {Value: "B=2"}, {Value: "A=1"}}, expected: true},
+		"spheron instance":   {other: []client.Env{{Value: "SPHERON_INSTANCE_ID=1"}, {Value: "B=2"}, {Value: "A=1"}, {Value: "SECRET=x", IsSecret: true}}, expected: true},
+		"changed value":      {other: []client.Env{{Value: "A=1"}, {Value: "B=3"}, {Value: "SECRET=x", IsSecret: true}}, expected: false},
+		"changed secret":     {other: []client.Env{{Value: "A=1"}, {Value: "B=2"}, {Value: "SECRET=x"}}, expected: false},
+		"missing variable":   {other: []client.Env{{Value: "A=1"}, {Value: "SECRET=x", IsSecret: true}}, expected: false},
+		"different variable": {other: []client.Env{{Value: "A=1"}, {Value: "C=2"}, {Value: "SECRET=x", IsSecret: true}}, expected: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := clientEnvsEqual(envs, tc.other); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMapClientEnvsToEnvsValue(t *testing.T) {
+	order := loadOrderFixture(t, "order_no_protocol_data.json")
+
+	got := mapClientEnvsToEnvsValue(order.ClusterInstanceConfiguration.Env, false)
+
+	expected := []Env{
+		{Key: types.StringValue("POSTGRES_PASSWORD"), Value: types.StringValue("secret")},
+		{Key: types.StringValue("EMPTY"), Value: types.StringValue("")},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d envs, got %d", len(expected), len(got))
+	}
+
+	for i, value := range got {
+		expectedValue := types.ObjectValueMust(getEnvAtrTypes(), map[string]attr.Value{
+			"key":   expected[i].Key,
+			"value": expected[i].Value,
+		})
+
+		if !value.Equal(expectedValue) {
+			t.Errorf("expected %s, got %s", expectedValue, value)
+		}
+	}
+
+	instanceOrder := loadOrderFixture(t, "order_url_preview.json")
+	if got := mapClientEnvsToEnvsValue(instanceOrder.ClusterInstanceConfiguration.Env, false); len(got) != 1 {
+		t.Errorf("expected SPHERON_INSTANCE_ID to be skipped, got %v", got)
+	}
+}
+
+func TestPersistentStorageClass(t *testing.T) {
+	for class, value := range map[string]string{"HDD": "beta1", "SSD": "beta2", "NVMe": "beta3"} {
+		got, err := GetPersistentStorageClassEnum(class)
+		if err != nil || got != value {
+			t.Errorf("GetPersistentStorageClassEnum(%q): expected %q, got %q (%v)", class, value, got, err)
+		}
+
+		got, err = GetStorageClassFromValue(value)
+		if err != nil || got != class {
+			t.Errorf("GetStorageClassFromValue(%q): expected %q, got %q (%v)", value, class, got, err)
+		}
+	}
+
+	if _, err := GetPersistentStorageClassEnum("Tape"); err == nil || !strings.Contains(err.Error(), "Tape") {
+		t.Errorf("expected error mentioning the unsupported class, got %v", err)
+	}
+
+	if _, err := GetStorageClassFromValue("beta4"); err == nil {
+		t.Error("expected error for unknown storage class value")
+	}
+}
+
+func TestRemoveGiSuffix(t *testing.T) {
+	testCases := map[string]string{
+		"10Gi":  "10",
+		"0.5Gi": "0.5",
+		"1":     "1",
+		"10":    "10",
+		"":      "",
+	}
+
+	for input, expected := range testCases {
+		if got := RemoveGiSuffix(input); got != expected {
+			t.Errorf("RemoveGiSuffix(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestMaskSensitiveValues(t *testing.T) {
+	var output bytes.Buffer
+
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = maskSensitiveValues(ctx, getEnvValues([]Env{
+		{Key: types.StringValue("PASSWORD"), Value: types.StringValue("s3cret")},
+		{Key: types.StringValue("EMPTY"), Value: types.StringValue("")},
+	})...)
+
+	tflog.Debug(ctx, "deploying with s3cret", map[string]any{
+		"Authorization": "Bearer token-value",
+		"env":           "PASSWORD=s3cret",
+	})
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("unable to decode log output: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+
+	expected := map[string]interface{}{
+		"@level":        "debug",
+		"@message":      "deploying with ***",
+		"@module":       "provider",
+		"Authorization": "***",
+		"env":           "PASSWORD=***",
+	}
+	if !reflect.DeepEqual(entries[0], expected) {
+		t.Errorf("expected %v, got %v", expected, entries[0])
+	}
+}
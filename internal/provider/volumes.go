@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// getPersistentStorageSpecs maps the deprecated persistent storage or the volumes to the volumes of the instance.
+func getPersistentStorageSpecs(ctx context.Context, persistentStorage types.Object, volumes []Volume) ([]client.PersistentStorage, bool) {
+	if persistentStorage.IsUnknown() {
+		return nil, false
+	}
+
+	if !persistentStorage.IsNull() {
+		var pStorage PersistentStorage
+		persistentStorage.As(ctx, &pStorage, basetypes.ObjectAsOptions{})
+		if pStorage.Class.IsUnknown() || pStorage.MountPoint.IsUnknown() || pStorage.Size.IsUnknown() {
+			return nil, false
+		}
+
+		class, _ := GetPersistentStorageClassEnum(pStorage.Class.ValueString())
+		return []client.PersistentStorage{{
+			Class:      class,
+			MountPoint: pStorage.MountPoint.ValueString(),
+			Size:       fmt.Sprintf("%dGi", int(pStorage.Size.ValueInt64())),
+		}}, true
+	}
+
+	var specs []client.PersistentStorage
+	for _, volume := range volumes {
+		if volume.Name.IsUnknown() || volume.Class.IsUnknown() || volume.MountPoint.IsUnknown() || volume.Size.IsUnknown() || volume.ReadOnly.IsUnknown() {
+			return nil, false
+		}
+
+		class, _ := GetPersistentStorageClassEnum(volume.Class.ValueString())
+		specs = append(specs, client.PersistentStorage{
+			Name:       volume.Name.ValueString(),
+			Class:      class,
+			MountPoint: volume.MountPoint.ValueString(),
+			Size:       fmt.Sprintf("%dGi", int(volume.Size.ValueInt64())),
+			ReadOnly:   volume.ReadOnly.ValueBool(),
+		})
+	}
+
+	return specs, true
+}
+
+func mapClientPersistentStorage(pStorage client.PersistentStorage) types.Object {
+	class, _ := GetStorageClassFromValue(pStorage.Class)
+	size, _ := strconv.Atoi(RemoveGiSuffix(pStorage.Size))
+
+	return types.ObjectValueMust(persistentStorageAttrTypes, map[string]attr.Value{
+		"class":       types.StringValue(class),
+		"mount_point": types.StringValue(pStorage.MountPoint),
+		"size":        types.Int64Value(int64(size)),
+	})
+}
+
+func mapClientPersistentStorageToVolumes(volumes []client.PersistentStorage) []Volume {
+	mapped := []Volume{}
+	for _, volume := range volumes {
+		class, _ := GetStorageClassFromValue(volume.Class)
+		size, _ := strconv.Atoi(RemoveGiSuffix(volume.Size))
+
+		mapped = append(mapped, Volume{
+			Name:       types.StringValue(volume.Name),
+			Class:      types.StringValue(class),
+			MountPoint: types.StringValue(volume.MountPoint),
+			Size:       types.Int64Value(int64(size)),
+			ReadOnly:   types.BoolValue(volume.ReadOnly),
+		})
+	}
+
+	return mapped
+}
+
+// validateVolumes checks that volume names and mount points are unique within the instance.
+func validateVolumes(volumes []Volume) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	names := map[string]bool{}
+	mountPoints := map[string]bool{}
+	for i, volume := range volumes {
+		if !volume.Name.IsUnknown() {
+			if names[volume.Name.ValueString()] {
+				diags.AddAttributeError(path.Root("volume").AtListIndex(i).AtName("name"), "Duplicate volume name.", fmt.Sprintf("Volume name %q is used more than once.", volume.Name.ValueString()))
+			}
+			names[volume.Name.ValueString()] = true
+		}
+
+		if !volume.MountPoint.IsUnknown() {
+			if mountPoints[volume.MountPoint.ValueString()] {
+				diags.AddAttributeError(path.Root("volume").AtListIndex(i).AtName("mount_point"), "Duplicate volume mount point.", fmt.Sprintf("Mount point %q is used by more than one volume.", volume.MountPoint.ValueString()))
+			}
+			mountPoints[volume.MountPoint.ValueString()] = true
+		}
+	}
+
+	return diags
+}
+
+func requiresReplaceIfVolumeAddedOrRemoved(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsUnknown() && len(req.PlanValue.Elements()) != len(req.StateValue.Elements())
+}
+
+func requiresReplaceIfVolumeShrunk(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !req.PlanValue.IsUnknown() && req.PlanValue.ValueInt64() < req.StateValue.ValueInt64()
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"terraform-provider-spheron/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPersistentStorageWireFormat(t *testing.T) {
+	order := loadOrderFixture(t, "order_no_protocol_data.json")
+
+	legacy := []client.PersistentStorage{{Class: "beta2", MountPoint: "/var/lib/postgresql/data", Size: "5Gi"}}
+	if volumes := order.ClusterInstanceConfiguration.AgreedMachineImage.GetVolumes(); !reflect.DeepEqual(volumes, legacy) {
+		t.Errorf("expected %+v, got %+v", legacy, volumes)
+	}
+
+	testCases := map[string]struct {
+		volumes  []client.PersistentStorage
+		expected string
+	}{
+		"no volumes":         {expected: `{"storage":"10Gi"}`},
+		"persistent storage": {volumes: legacy, expected: `{"persistentStorage":{"class":"beta2","mountPoint":"/var/lib/postgresql/data","size":"5Gi"},"storage":"10Gi"}`},
+		"volumes": {
+			volumes:  []client.PersistentStorage{{Name: "data", Class: "beta3", MountPoint: "/data", Size: "10Gi"}},
+			expected: `{"volumes":[{"name":"data","class":"beta3","mountPoint":"/data","size":"10Gi"}],"storage":"10Gi"}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			specs := client.CustomInstanceSpecs{Storage: "10Gi"}
+			specs.SetVolumes(tc.volumes)
+
+			data, err := json.Marshal(specs)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if string(data) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, data)
+			}
+			if volumes := specs.GetVolumes(); len(tc.volumes) != 0 && !reflect.DeepEqual(volumes, tc.volumes) {
+				t.Errorf("expected %+v, got %+v", tc.volumes, volumes)
+			}
+		})
+	}
+}
+
+func TestGetPersistentStorageSpecs(t *testing.T) {
+	ctx := context.Background()
+	persistentStorageNull := types.ObjectNull(persistentStorageAttrTypes)
+
+	volumes := []Volume{
+		{Name: types.StringValue("data"), Class: types.StringValue("NVMe"), MountPoint: types.StringValue("/var/lib/postgresql/data"), Size: types.Int64Value(100), ReadOnly: types.BoolValue(false)},
+		{Name: types.StringValue("wal"), Class: types.StringValue("SSD"), MountPoint: types.StringValue("/var/lib/postgresql/wal"), Size: types.Int64Value(20), ReadOnly: types.BoolValue(true)},
+	}
+	specs, ok := getPersistentStorageSpecs(ctx, persistentStorageNull, volumes)
+	expected := []client.PersistentStorage{
+		{Name: "data", Class: "beta3", MountPoint: "/var/lib/postgresql/data", Size: "100Gi"},
+		{Name: "wal", Class: "beta2", MountPoint: "/var/lib/postgresql/wal", Size: "20Gi", ReadOnly: true},
+	}
+	if !ok || !reflect.DeepEqual(specs, expected) {
+		t.Errorf("expected %+v, got %+v (%v)", expected, specs, ok)
+	}
+
+	if mapped := mapClientPersistentStorageToVolumes(specs); !reflect.DeepEqual(mapped, volumes) {
+		t.Errorf("expected %+v, got %+v", volumes, mapped)
+	}
+
+	persistentStorage := types.ObjectValueMust(persistentStorageAttrTypes, map[string]attr.Value{
+		"class":       types.StringValue("HDD"),
+		"mount_point": types.StringValue("/data"),
+		"size":        types.Int64Value(10),
+	})
+	specs, ok = getPersistentStorageSpecs(ctx, persistentStorage, nil)
+	expected = []client.PersistentStorage{{Class: "beta1", MountPoint: "/data", Size: "10Gi"}}
+	if !ok || !reflect.DeepEqual(specs, expected) {
+		t.Errorf("expected %+v, got %+v (%v)", expected, specs, ok)
+	}
+	if mapped := mapClientPersistentStorage(specs[0]); !mapped.Equal(persistentStorage) {
+		t.Errorf("expected %s, got %s", persistentStorage, mapped)
+	}
+
+	volumes[1].Size = types.Int64Unknown()
+	if _, ok := getPersistentStorageSpecs(ctx, persistentStorageNull, volumes); ok {
+		t.Error("expected no volumes for unknown size")
+	}
+	if _, ok := getPersistentStorageSpecs(ctx, types.ObjectUnknown(persistentStorageAttrTypes), nil); ok {
+		t.Error("expected no volumes for unknown persistent storage")
+	}
+}
+
+func TestValidateVolumes(t *testing.T) {
+	volumes := []Volume{
+		{Name: types.StringValue("data"), MountPoint: types.StringValue("/data")},
+		{Name: types.StringValue("wal"), MountPoint: types.StringUnknown()},
+	}
+	if diags := validateVolumes(volumes); diags.HasError() {
+		t.Errorf("expected no errors, got %v", diags)
+	}
+
+	volumes = append(volumes, Volume{Name: types.StringValue("data"), MountPoint: types.StringValue("/data")})
+	diags := validateVolumes(volumes)
+	if diags.ErrorsCount() != 2 {
+		t.Fatalf("expected 2 errors, got %v", diags)
+	}
+	if detail := diags.Errors()[0].Detail(); detail != `Volume name "data" is used more than once.` {
+		t.Errorf("unexpected error %q", detail)
+	}
+	if detail := diags.Errors()[1].Detail(); detail != `Mount point "/data" is used by more than one volume.` {
+		t.Errorf("unexpected error %q", detail)
+	}
+}